@@ -21,6 +21,8 @@ PORT="${PORT:-8081}"
 DURATION=${DURATION:-300}  # Default 5 minutes
 REQUEST_INTERVAL=${REQUEST_INTERVAL:-2}  # Seconds between requests
 VERBOSE=${VERBOSE:-false}
+# API key for catalog writes; the default is the lab key from k8s/apps/catalog/auth-secret.yaml
+CATALOG_API_KEY="${CATALOG_API_KEY:-catalog-lab-key}"
 
 # Service Configuration
 declare -A SERVICES=(
@@ -149,6 +151,7 @@ seed_catalog_products() {
     for product in "${products[@]}"; do
         local response=$(curl -s -w "\n%{http_code}" -X POST "${base_url}/api/v1/products" \
             -H "Content-Type: application/json" \
+            -H "X-API-Key: ${CATALOG_API_KEY}" \
             -d "$product" 2>/dev/null)
         
         local http_code=$(echo "$response" | tail -n1)
@@ -297,6 +300,7 @@ simulate_catalog_stock_update() {
     
     local response=$(curl -s -w "\n%{http_code}" -X PUT "${base_url}/api/v1/products/${product_id}" \
         -H "Content-Type: application/json" \
+        -H "X-API-Key: ${CATALOG_API_KEY}" \
         -d "$update_data" 2>/dev/null)
     
     local http_code=$(echo "$response" | tail -n1)
@@ -576,6 +580,9 @@ show_usage() {
     echo "  $0 --seed-only                       # Only seed data"
     echo "  $0 --no-seed --duration 120          # Skip seeding, 2 minutes traffic"
     echo ""
+    echo "Environment:"
+    echo "  CATALOG_API_KEY           API key for catalog writes (default: the lab key)"
+    echo ""
     echo "Prerequisites:"
     echo "  Add these entries to /etc/hosts:"
     echo "    127.0.0.1 catalog.kubelab.lan"
@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// APIKeyHeader is the request header that carries an API key
+const APIKeyHeader = "X-API-Key"
+
+// apiKey is the SHA-256 of a key and who it belongs to
+type apiKey struct {
+	principal string
+	hash      []byte
+}
+
+// APIKeys authenticates requests by the API key in the X-API-Key header. Only the SHA-256
+// of each key is configured, so the config and the secret holding it do not reveal the keys.
+type APIKeys struct {
+	keys []apiKey
+}
+
+// LoadAPIKeys parses principal:sha256-hex entries, from the list and from the file (if named).
+// The file has one entry per line; blank lines and lines starting with # are skipped.
+func LoadAPIKeys(entries []string, path string) (*APIKeys, error) {
+	store := &APIKeys{}
+	for i, entry := range entries {
+		if err := store.add(entry); err != nil {
+			return nil, fmt.Errorf("API key %d: %w", i+1, err)
+		}
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read API keys file: %w", err)
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for line := 1; scanner.Scan(); line++ {
+			entry := strings.TrimSpace(scanner.Text())
+			if entry == "" || strings.HasPrefix(entry, "#") {
+				continue
+			}
+			if err := store.add(entry); err != nil {
+				return nil, fmt.Errorf("API keys file %s, line %d: %w", path, line, err)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read API keys file: %w", err)
+		}
+	}
+
+	if len(store.keys) == 0 {
+		return nil, fmt.Errorf("no API keys configured")
+	}
+	return store, nil
+}
+
+// add parses and stores one principal:sha256-hex entry
+func (s *APIKeys) add(entry string) error {
+	principal, digest, ok := strings.Cut(entry, ":")
+	principal = strings.TrimSpace(principal)
+	if !ok || principal == "" {
+		return fmt.Errorf("expected principal:sha256-hex")
+	}
+	hash, err := hex.DecodeString(strings.TrimSpace(digest))
+	if err != nil || len(hash) != sha256.Size {
+		return fmt.Errorf("key of %s is not a hex-encoded SHA-256", principal)
+	}
+	for _, key := range s.keys {
+		if bytes.Equal(key.hash, hash) {
+			return fmt.Errorf("key of %s is also the key of %s", principal, key.principal)
+		}
+	}
+	s.keys = append(s.keys, apiKey{principal: principal, hash: hash})
+	return nil
+}
+
+// Authenticate implements Authenticator
+func (s *APIKeys) Authenticate(r *http.Request) (*Principal, error) {
+	key := r.Header.Get(APIKeyHeader)
+	if key == "" {
+		return nil, ErrNoCredentials
+	}
+
+	// Compare with every key, so the time taken does not tell which one was close
+	hash := sha256.Sum256([]byte(key))
+	var match *apiKey
+	for i := range s.keys {
+		if subtle.ConstantTimeCompare(s.keys[i].hash, hash[:]) == 1 {
+			match = &s.keys[i]
+		}
+	}
+	if match == nil {
+		return nil, invalid("unknown API key")
+	}
+	return &Principal{ID: match.principal, Method: MethodAPIKey}, nil
+}
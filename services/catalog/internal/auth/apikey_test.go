@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// keyEntry returns the principal:sha256-hex config entry for a key
+func keyEntry(principal, key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return principal + ":" + hex.EncodeToString(hash[:])
+}
+
+func TestAPIKeysAuthenticate(t *testing.T) {
+	keys, err := LoadAPIKeys([]string{
+		keyEntry("inventory-sync", "sync-secret"),
+		keyEntry("admin", "admin-secret"),
+	}, "")
+	if err != nil {
+		t.Fatalf("LoadAPIKeys() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		key       string
+		principal string
+		err       error
+	}{
+		{name: "first key", key: "sync-secret", principal: "inventory-sync"},
+		{name: "second key", key: "admin-secret", principal: "admin"},
+		{name: "no key", key: "", err: ErrNoCredentials},
+		{name: "unknown key", key: "guess", err: ErrInvalidCredentials},
+		{name: "key with a different case", key: "Admin-Secret", err: ErrInvalidCredentials},
+		{name: "the hash instead of the key", key: strings.TrimPrefix(keyEntry("", "admin-secret"), ":"), err: ErrInvalidCredentials},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/api/v1/products", nil)
+			if tt.key != "" {
+				r.Header.Set(APIKeyHeader, tt.key)
+			}
+
+			principal, err := keys.Authenticate(r)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Authenticate() error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if principal.ID != tt.principal || principal.Method != MethodAPIKey {
+				t.Errorf("Authenticate() = %+v, want %s by %s", principal, tt.principal, MethodAPIKey)
+			}
+		})
+	}
+}
+
+func TestLoadAPIKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		file    string
+		keys    int
+		err     string
+	}{
+		{name: "entries", entries: []string{keyEntry("a", "1"), keyEntry("b", "2")}, keys: 2},
+		{
+			name: "file with comments and blank lines",
+			file: "# sync job\n" + keyEntry("sync", "1") + "\n\n  " + keyEntry("admin", "2") + "  \n",
+			keys: 2,
+		},
+		{name: "entries and file", entries: []string{keyEntry("a", "1")}, file: keyEntry("b", "2"), keys: 2},
+		{name: "nothing", err: "no API keys configured"},
+		{name: "no principal", entries: []string{strings.TrimPrefix(keyEntry("", "1"), ":")}, err: "API key 1: expected principal:sha256-hex"},
+		{name: "empty principal", entries: []string{keyEntry(" ", "1")}, err: "API key 1: expected principal:sha256-hex"},
+		{name: "not hex", entries: []string{"a:not-hex"}, err: "key of a is not a hex-encoded SHA-256"},
+		{name: "wrong length", entries: []string{"a:abcd"}, err: "key of a is not a hex-encoded SHA-256"},
+		{
+			name:    "same key twice",
+			entries: []string{keyEntry("a", "1"), keyEntry("b", "1")},
+			err:     "API key 2: key of b is also the key of a",
+		},
+		{name: "bad line in file", file: "# keys\n" + keyEntry("a", "1") + "\nb:xyz\n", err: "line 3: key of b is not a hex-encoded SHA-256"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var path string
+			if tt.file != "" {
+				path = filepath.Join(t.TempDir(), "api-keys")
+				if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			keys, err := LoadAPIKeys(tt.entries, path)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("LoadAPIKeys() error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadAPIKeys() error = %v", err)
+			}
+			if len(keys.keys) != tt.keys {
+				t.Errorf("loaded %d keys, want %d", len(keys.keys), tt.keys)
+			}
+		})
+	}
+}
+
+func TestLoadAPIKeysMissingFile(t *testing.T) {
+	_, err := LoadAPIKeys(nil, filepath.Join(t.TempDir(), "missing"))
+	if err == nil || !strings.Contains(err.Error(), "failed to read API keys file") {
+		t.Errorf("LoadAPIKeys() error = %v, want a read error", err)
+	}
+}
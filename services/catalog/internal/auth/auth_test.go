@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"catalog-service/internal/config"
+)
+
+func TestChain(t *testing.T) {
+	authenticator, err := New(config.AuthConfig{
+		Mode:      "required",
+		APIKeys:   []string{keyEntry("inventory-sync", "sync-secret")},
+		JWTSecret: testJWTSecret,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		apiKey        string
+		authorization string
+		method        string
+		err           error
+	}{
+		{name: "API key", apiKey: "sync-secret", method: MethodAPIKey},
+		{name: "bearer token", authorization: "Bearer x.y.z", err: ErrInvalidCredentials},
+		{name: "nothing", err: ErrNoCredentials},
+		{name: "other scheme", authorization: "Basic dXNlcjpwYXNz", err: ErrNoCredentials},
+		{
+			// A rejected key fails the request, whatever else it carries
+			name: "rejected key with a token", apiKey: "guess", authorization: "Bearer x.y.z",
+			err: ErrInvalidCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("DELETE", "/api/v1/products/1", nil)
+			if tt.apiKey != "" {
+				r.Header.Set(APIKeyHeader, tt.apiKey)
+			}
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+
+			principal, err := authenticator.Authenticate(r)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Authenticate() error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if principal.Method != tt.method {
+				t.Errorf("Authenticate() method = %s, want %s", principal.Method, tt.method)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.AuthConfig
+		wantNil bool
+		wantErr bool
+	}{
+		{name: "disabled", cfg: config.AuthConfig{Mode: "disabled", JWTSecret: testJWTSecret}, wantNil: true},
+		{name: "API keys", cfg: config.AuthConfig{Mode: "required", APIKeys: []string{keyEntry("a", "1")}}},
+		{name: "JWT secret", cfg: config.AuthConfig{Mode: "required", JWTSecret: testJWTSecret}},
+		{name: "nothing configured", cfg: config.AuthConfig{Mode: "required"}, wantErr: true},
+		{name: "invalid API key", cfg: config.AuthConfig{Mode: "required", APIKeys: []string{"a:b"}}, wantErr: true},
+		{name: "missing JWKS file", cfg: config.AuthConfig{Mode: "required", JWKSFile: "/nonexistent/jwks.json"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator, err := New(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (authenticator == nil) != tt.wantNil {
+				t.Errorf("New() = %v, want nil %v", authenticator, tt.wantNil)
+			}
+		})
+	}
+}
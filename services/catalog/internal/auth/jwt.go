@@ -0,0 +1,323 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256" // registers SHA-256 for crypto.Hash
+	_ "crypto/sha512" // registers SHA-384 and SHA-512 for crypto.Hash
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"catalog-service/internal/config"
+)
+
+// clockSkew is how far the clocks of the token issuer and this service may drift apart
+const clockSkew = time.Minute
+
+// minRSABits rejects RSA keys too short to be safe
+const minRSABits = 2048
+
+// signingAlgs maps the supported JWS algorithms to their hash. "none" is not among them.
+var signingAlgs = map[string]crypto.Hash{
+	"HS256": crypto.SHA256, "HS384": crypto.SHA384, "HS512": crypto.SHA512,
+	"RS256": crypto.SHA256, "RS384": crypto.SHA384, "RS512": crypto.SHA512,
+	"ES256": crypto.SHA256, "ES384": crypto.SHA384, "ES512": crypto.SHA512,
+}
+
+// ecCurves maps each ES algorithm to the only curve it may be used with
+var ecCurves = map[string]elliptic.Curve{
+	"ES256": elliptic.P256(),
+	"ES384": elliptic.P384(),
+	"ES512": elliptic.P521(),
+}
+
+// jwk is a public key of a JSON Web Key Set (RFC 7517)
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey is a parsed JWKS key
+type publicKey struct {
+	kid string
+	alg string
+	key crypto.PublicKey
+}
+
+// JWTVerifier authenticates requests by the bearer JWT in the Authorization header. Tokens are
+// signed with the HMAC secret or with a key of the JWKS file, and must carry sub and exp claims.
+type JWTVerifier struct {
+	secret   []byte
+	keys     []publicKey
+	issuer   string
+	audience string
+}
+
+// NewJWTVerifier returns a verifier for the JWT secret and JWKS file of the config
+func NewJWTVerifier(cfg config.AuthConfig) (*JWTVerifier, error) {
+	v := &JWTVerifier{
+		secret:   []byte(cfg.JWTSecret),
+		issuer:   cfg.JWTIssuer,
+		audience: cfg.JWTAudience,
+	}
+
+	if cfg.JWKSFile != "" {
+		keys, err := loadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		v.keys = keys
+	}
+	return v, nil
+}
+
+// loadJWKS reads the signing keys of a JWKS file. Encryption keys are skipped.
+func loadJWKS(path string) ([]publicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS file %s: %w", path, err)
+	}
+
+	var keys []publicKey
+	for i, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("JWKS file %s, key %d (kid %q): %w", path, i, k.Kid, err)
+		}
+		keys = append(keys, publicKey{kid: k.Kid, alg: k.Alg, key: key})
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("JWKS file %s has no signing keys", path)
+	}
+	return keys, nil
+}
+
+// publicKey decodes an RSA or EC key
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid n: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil || !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("invalid e")
+		}
+		if n.BitLen() < minRSABits {
+			return nil, fmt.Errorf("RSA key has %d bits, at least %d are required", n.BitLen(), minRSABits)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, errX := decodeBigInt(k.X)
+		y, errY := decodeBigInt(k.Y)
+		if errX != nil || errY != nil {
+			return nil, fmt.Errorf("invalid x or y")
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		// ECDH validates that the point is on the curve
+		if _, err := key.ECDH(); err != nil {
+			return nil, fmt.Errorf("invalid EC point: %w", err)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// Authenticate implements Authenticator
+func (v *JWTVerifier) Authenticate(r *http.Request) (*Principal, error) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return nil, ErrNoCredentials
+	}
+
+	subject, err := v.Verify(strings.TrimSpace(token))
+	if err != nil {
+		return nil, err
+	}
+	return &Principal{ID: subject, Method: MethodJWT}, nil
+}
+
+// Verify checks the signature and claims of a compact JWT and returns its subject
+func (v *JWTVerifier) Verify(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", invalid("token is not a JWT")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return "", invalid("malformed header: %v", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", invalid("malformed signature")
+	}
+	if err := v.verifySignature(header.Alg, header.Kid, parts[0]+"."+parts[1], signature); err != nil {
+		return "", err
+	}
+
+	// The claims are only read once the signature proves who wrote them
+	var claims struct {
+		Subject   string   `json:"sub"`
+		Issuer    string   `json:"iss"`
+		Audience  audience `json:"aud"`
+		ExpiresAt *float64 `json:"exp"`
+		NotBefore *float64 `json:"nbf"`
+	}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return "", invalid("malformed claims: %v", err)
+	}
+
+	// NumericDate claims are seconds since the epoch
+	now := float64(time.Now().UnixMilli()) / 1000
+	skew := clockSkew.Seconds()
+	switch {
+	case claims.ExpiresAt == nil:
+		return "", invalid("token has no exp claim")
+	case now > *claims.ExpiresAt+skew:
+		return "", invalid("token expired")
+	case claims.NotBefore != nil && now < *claims.NotBefore-skew:
+		return "", invalid("token not valid yet")
+	case v.issuer != "" && claims.Issuer != v.issuer:
+		return "", invalid("token issuer %q is not %q", claims.Issuer, v.issuer)
+	case v.audience != "" && !slices.Contains(claims.Audience, v.audience):
+		return "", invalid("token audience does not include %q", v.audience)
+	case claims.Subject == "":
+		return "", invalid("token has no sub claim")
+	}
+	return claims.Subject, nil
+}
+
+// verifySignature checks the signature with the HMAC secret or the JWKS keys the algorithm
+// calls for. Deciding by the algorithm before picking a key stops a token signed with the
+// public key as an HMAC secret from passing.
+func (v *JWTVerifier) verifySignature(alg, kid, signingInput string, signature []byte) error {
+	hash, ok := signingAlgs[alg]
+	if !ok {
+		return invalid("unsupported algorithm %q", alg)
+	}
+	if strings.HasPrefix(alg, "HS") {
+		if len(v.secret) == 0 {
+			return invalid("HMAC tokens are not accepted")
+		}
+		mac := hmac.New(hash.New, v.secret)
+		mac.Write([]byte(signingInput))
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return invalid("signature mismatch")
+		}
+		return nil
+	}
+
+	h := hash.New()
+	h.Write([]byte(signingInput))
+	digest := h.Sum(nil)
+	for _, k := range v.keys {
+		if (kid != "" && k.kid != kid) || (k.alg != "" && k.alg != alg) {
+			continue
+		}
+		switch key := k.key.(type) {
+		case *rsa.PublicKey:
+			if strings.HasPrefix(alg, "RS") && rsa.VerifyPKCS1v15(key, hash, digest, signature) == nil {
+				return nil
+			}
+		case *ecdsa.PublicKey:
+			if strings.HasPrefix(alg, "ES") && key.Curve == ecCurves[alg] && verifyES(key, digest, signature) {
+				return nil
+			}
+		}
+	}
+	return invalid("no key verifies the signature (alg %s, kid %q)", alg, kid)
+}
+
+// verifyES checks a JWS ECDSA signature, which is r and s as fixed-size big-endian integers
+func verifyES(key *ecdsa.PublicKey, digest, signature []byte) bool {
+	size := (key.Curve.Params().BitSize + 7) / 8
+	if len(signature) != 2*size {
+		return false
+	}
+	r := new(big.Int).SetBytes(signature[:size])
+	s := new(big.Int).SetBytes(signature[size:])
+	return ecdsa.Verify(key, digest, r, s)
+}
+
+// audience is the aud claim, which is a single string or an array of them
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("aud is neither a string nor an array of strings")
+	}
+	*a = list
+	return nil
+}
+
+// decodeSegment decodes a base64url JSON segment of a JWT into dest
+func decodeSegment(segment string, dest any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dest)
+}
+
+// decodeBigInt decodes a base64url unsigned big-endian integer of a JWK
+func decodeBigInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,338 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"catalog-service/internal/config"
+)
+
+const testJWTSecret = "0123456789abcdef0123456789abcdef"
+
+// testKeys are the keys the JWT tests sign with; the JWKS file holds their public halves
+type testKeys struct {
+	rsa  *rsa.PrivateKey
+	ec   *ecdsa.PrivateKey
+	jwks string
+}
+
+func newTestKeys(t *testing.T) testKeys {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b64 := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	jwks, err := json.Marshal(map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa-1", "use": "sig", "alg": "RS256",
+			"n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kty": "EC", "kid": "ec-1", "crv": "P-256",
+			"x": b64(ecKey.X.FillBytes(make([]byte, 32))), "y": b64(ecKey.Y.FillBytes(make([]byte, 32)))},
+		// Encryption keys are skipped, even when they could not be parsed
+		{"kty": "oct", "kid": "enc-1", "use": "enc"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return testKeys{rsa: rsaKey, ec: ecKey, jwks: path}
+}
+
+// signToken returns a compact JWT with the header and claims, signed by sign
+func signToken(t *testing.T, header, claims map[string]any, sign func(input []byte) []byte) string {
+	t.Helper()
+	segment := func(v any) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	input := segment(header) + "." + segment(claims)
+	return input + "." + base64.RawURLEncoding.EncodeToString(sign([]byte(input)))
+}
+
+func hmacSigner(hash crypto.Hash, secret []byte) func([]byte) []byte {
+	return func(input []byte) []byte {
+		mac := hmac.New(hash.New, secret)
+		mac.Write(input)
+		return mac.Sum(nil)
+	}
+}
+
+func rsaSigner(t *testing.T, key *rsa.PrivateKey) func([]byte) []byte {
+	return func(input []byte) []byte {
+		digest := crypto.SHA256.New()
+		digest.Write(input)
+		signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest.Sum(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signature
+	}
+}
+
+func ecSigner(t *testing.T, key *ecdsa.PrivateKey) func([]byte) []byte {
+	return func(input []byte) []byte {
+		digest := crypto.SHA256.New()
+		digest.Write(input)
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest.Sum(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+}
+
+func TestJWTVerifierVerify(t *testing.T) {
+	keys := newTestKeys(t)
+	verifier, err := NewJWTVerifier(config.AuthConfig{
+		JWTSecret:   testJWTSecret,
+		JWKSFile:    keys.jwks,
+		JWTIssuer:   "https://auth.kubelab.lan",
+		JWTAudience: "catalog",
+	})
+	if err != nil {
+		t.Fatalf("NewJWTVerifier() error = %v", err)
+	}
+
+	now := time.Now()
+	claims := func(changes map[string]any) map[string]any {
+		c := map[string]any{
+			"sub": "inventory-sync",
+			"iss": "https://auth.kubelab.lan",
+			"aud": "catalog",
+			"exp": now.Add(time.Hour).Unix(),
+		}
+		for k, v := range changes {
+			if v == nil {
+				delete(c, k)
+			} else {
+				c[k] = v
+			}
+		}
+		return c
+	}
+	hs256 := map[string]any{"alg": "HS256", "typ": "JWT"}
+	secret := hmacSigner(crypto.SHA256, []byte(testJWTSecret))
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(&keys.rsa.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		err   string
+	}{
+		{name: "HS256", token: signToken(t, hs256, claims(nil), secret)},
+		{
+			name:  "HS512",
+			token: signToken(t, map[string]any{"alg": "HS512"}, claims(nil), hmacSigner(crypto.SHA512, []byte(testJWTSecret))),
+		},
+		{
+			name:  "RS256 from the JWKS",
+			token: signToken(t, map[string]any{"alg": "RS256", "kid": "rsa-1"}, claims(nil), rsaSigner(t, keys.rsa)),
+		},
+		{
+			name:  "RS256 without a kid",
+			token: signToken(t, map[string]any{"alg": "RS256"}, claims(nil), rsaSigner(t, keys.rsa)),
+		},
+		{
+			name:  "ES256 from the JWKS",
+			token: signToken(t, map[string]any{"alg": "ES256", "kid": "ec-1"}, claims(nil), ecSigner(t, keys.ec)),
+		},
+		{name: "audience list", token: signToken(t, hs256, claims(map[string]any{"aud": []string{"orders", "catalog"}}), secret)},
+		{name: "expired within the clock skew", token: signToken(t, hs256, claims(map[string]any{"exp": now.Add(-30 * time.Second).Unix()}), secret)},
+		{name: "not before within the clock skew", token: signToken(t, hs256, claims(map[string]any{"nbf": now.Add(30 * time.Second).Unix()}), secret)},
+
+		{name: "expired", token: signToken(t, hs256, claims(map[string]any{"exp": now.Add(-2 * time.Minute).Unix()}), secret), err: "token expired"},
+		{name: "no exp", token: signToken(t, hs256, claims(map[string]any{"exp": nil}), secret), err: "token has no exp claim"},
+		{name: "not valid yet", token: signToken(t, hs256, claims(map[string]any{"nbf": now.Add(2 * time.Minute).Unix()}), secret), err: "token not valid yet"},
+		{name: "no sub", token: signToken(t, hs256, claims(map[string]any{"sub": nil}), secret), err: "token has no sub claim"},
+		{name: "other issuer", token: signToken(t, hs256, claims(map[string]any{"iss": "https://evil.example"}), secret), err: "token issuer"},
+		{name: "other audience", token: signToken(t, hs256, claims(map[string]any{"aud": "orders"}), secret), err: "token audience"},
+		{name: "wrong secret", token: signToken(t, hs256, claims(nil), hmacSigner(crypto.SHA256, []byte("another-secret-another-secret-32"))), err: "signature mismatch"},
+		{
+			name:  "tampered claims",
+			token: tamper(signToken(t, hs256, claims(nil), secret), claims(map[string]any{"sub": "admin"})),
+			err:   "signature mismatch",
+		},
+
+		{name: "alg none", token: signToken(t, map[string]any{"alg": "none"}, claims(nil), func([]byte) []byte { return nil }), err: `unsupported algorithm "none"`},
+		{name: "alg missing", token: signToken(t, map[string]any{"typ": "JWT"}, claims(nil), secret), err: `unsupported algorithm ""`},
+		{name: "alg PS256", token: signToken(t, map[string]any{"alg": "PS256"}, claims(nil), rsaSigner(t, keys.rsa)), err: `unsupported algorithm "PS256"`},
+		{
+			// The RSA public key is no HMAC secret, whatever the token claims
+			name:  "HS256 signed with the RSA public key",
+			token: signToken(t, map[string]any{"alg": "HS256", "kid": "rsa-1"}, claims(nil), hmacSigner(crypto.SHA256, publicKeyDER)),
+			err:   "signature mismatch",
+		},
+		{
+			name:  "RS256 signed with the HMAC secret",
+			token: signToken(t, map[string]any{"alg": "RS256", "kid": "rsa-1"}, claims(nil), secret),
+			err:   "no key verifies the signature",
+		},
+		{
+			name:  "RS384 with a key for RS256",
+			token: signToken(t, map[string]any{"alg": "RS384", "kid": "rsa-1"}, claims(nil), rsaSigner(t, keys.rsa)),
+			err:   "no key verifies the signature",
+		},
+		{
+			name:  "ES384 with a P-256 key",
+			token: signToken(t, map[string]any{"alg": "ES384", "kid": "ec-1"}, claims(nil), ecSigner(t, keys.ec)),
+			err:   "no key verifies the signature",
+		},
+		{
+			name:  "unknown kid",
+			token: signToken(t, map[string]any{"alg": "RS256", "kid": "rsa-2"}, claims(nil), rsaSigner(t, keys.rsa)),
+			err:   "no key verifies the signature",
+		},
+
+		{name: "not a JWT", token: "not-a-jwt", err: "token is not a JWT"},
+		{name: "malformed header", token: "e30x.e30.c2ln", err: "malformed header"},
+		{name: "malformed signature", token: signToken(t, hs256, claims(nil), secret) + "!", err: "malformed signature"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subject, err := verifier.Verify(tt.token)
+			if tt.err != "" {
+				if !errors.Is(err, ErrInvalidCredentials) || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Verify() = %q, %v, want an invalid credentials error with %q", subject, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if subject != "inventory-sync" {
+				t.Errorf("Verify() subject = %q, want inventory-sync", subject)
+			}
+		})
+	}
+}
+
+// tamper replaces the claims of a signed token, keeping its header and signature
+func tamper(token string, claims map[string]any) string {
+	parts := strings.Split(token, ".")
+	data, _ := json.Marshal(claims)
+	parts[1] = base64.RawURLEncoding.EncodeToString(data)
+	return strings.Join(parts, ".")
+}
+
+func TestJWTVerifierWithoutSecretRejectsHMAC(t *testing.T) {
+	keys := newTestKeys(t)
+	verifier, err := NewJWTVerifier(config.AuthConfig{JWKSFile: keys.jwks})
+	if err != nil {
+		t.Fatalf("NewJWTVerifier() error = %v", err)
+	}
+
+	// With no secret configured, an empty HMAC key must not verify anything
+	claims := map[string]any{"sub": "admin", "exp": time.Now().Add(time.Hour).Unix()}
+	token := signToken(t, map[string]any{"alg": "HS256"}, claims, hmacSigner(crypto.SHA256, nil))
+	if _, err := verifier.Verify(token); !errors.Is(err, ErrInvalidCredentials) || !strings.Contains(err.Error(), "HMAC tokens are not accepted") {
+		t.Errorf("Verify() error = %v, want HMAC tokens rejected", err)
+	}
+}
+
+func TestJWTVerifierAuthenticate(t *testing.T) {
+	verifier, err := NewJWTVerifier(config.AuthConfig{JWTSecret: testJWTSecret})
+	if err != nil {
+		t.Fatalf("NewJWTVerifier() error = %v", err)
+	}
+	claims := map[string]any{"sub": "inventory-sync", "exp": time.Now().Add(time.Hour).Unix()}
+	token := signToken(t, map[string]any{"alg": "HS256"}, claims, hmacSigner(crypto.SHA256, []byte(testJWTSecret)))
+
+	tests := []struct {
+		name          string
+		authorization string
+		err           error
+	}{
+		{name: "bearer token", authorization: "Bearer " + token},
+		{name: "scheme is case-insensitive", authorization: "bearer " + token},
+		{name: "no header", authorization: "", err: ErrNoCredentials},
+		{name: "basic auth", authorization: "Basic dXNlcjpwYXNz", err: ErrNoCredentials},
+		{name: "scheme only", authorization: "Bearer", err: ErrNoCredentials},
+		{name: "invalid token", authorization: "Bearer " + token + "x", err: ErrInvalidCredentials},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/api/v1/products", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+
+			principal, err := verifier.Authenticate(r)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Authenticate() error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if principal.ID != "inventory-sync" || principal.Method != MethodJWT {
+				t.Errorf("Authenticate() = %+v", principal)
+			}
+		})
+	}
+}
+
+func TestLoadJWKSRejectsWeakKeys(t *testing.T) {
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b64 := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+	tests := []struct {
+		name string
+		key  map[string]string
+		err  string
+	}{
+		{name: "short RSA key", key: map[string]string{"kty": "RSA", "n": b64(small.N.Bytes()), "e": "AQAB"}, err: "RSA key has 1024 bits"},
+		{name: "exponent too small", key: map[string]string{"kty": "RSA", "n": b64(small.N.Bytes()), "e": "Ag"}, err: "invalid e"},
+		{name: "unknown curve", key: map[string]string{"kty": "EC", "crv": "secp256k1", "x": "AQ", "y": "AQ"}, err: `unsupported curve "secp256k1"`},
+		{name: "point off the curve", key: map[string]string{"kty": "EC", "crv": "P-256", "x": "AQ", "y": "AQ"}, err: "invalid EC point"},
+		{name: "symmetric key", key: map[string]string{"kty": "oct"}, err: `unsupported key type "oct"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, _ := json.Marshal(map[string]any{"keys": []map[string]string{tt.key}})
+			path := filepath.Join(t.TempDir(), "jwks.json")
+			if err := os.WriteFile(path, data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := loadJWKS(path); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("loadJWKS() error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,517 @@
+// Package config loads the service settings from defaults, an optional YAML file
+// and environment variables, in that order of precedence (environment wins).
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds every setting of the catalog service
+type Config struct {
+	Server       ServerConfig      `yaml:"server" json:"server"`
+	Database     DatabaseConfig    `yaml:"database" json:"database"`
+	Tracing      TracingConfig     `yaml:"tracing" json:"tracing"`
+	Metrics      MetricsConfig     `yaml:"metrics" json:"metrics"`
+	Log          LogConfig         `yaml:"log" json:"log"`
+	Reservations ReservationConfig `yaml:"reservations" json:"reservations"`
+	Auth         AuthConfig        `yaml:"auth" json:"auth"`
+}
+
+// ServerConfig holds the listener and shutdown settings
+type ServerConfig struct {
+	Port     string `yaml:"port" json:"port"`
+	GRPCPort string `yaml:"grpc_port" json:"grpc_port"`
+	// ShutdownDelay is how long the service keeps serving with failing readiness after SIGTERM,
+	// so load balancers stop sending traffic before the listeners close
+	ShutdownDelay Duration `yaml:"shutdown_delay" json:"shutdown_delay"`
+	// ShutdownTimeout bounds how long in-flight requests may take to drain
+	ShutdownTimeout Duration `yaml:"shutdown_timeout" json:"shutdown_timeout"`
+}
+
+// DatabaseConfig holds the PostgreSQL connection and pool settings
+type DatabaseConfig struct {
+	Host            string   `yaml:"host" json:"host"`
+	Port            string   `yaml:"port" json:"port"`
+	User            string   `yaml:"user" json:"user"`
+	Password        string   `yaml:"password" json:"password"`
+	Name            string   `yaml:"name" json:"name"`
+	SSLMode         string   `yaml:"sslmode" json:"sslmode"`
+	MaxOpenConns    int      `yaml:"max_open_conns" json:"max_open_conns"`
+	MaxIdleConns    int      `yaml:"max_idle_conns" json:"max_idle_conns"`
+	ConnMaxLifetime Duration `yaml:"conn_max_lifetime" json:"conn_max_lifetime"`
+}
+
+// TracingConfig holds the OpenTelemetry tracing settings
+type TracingConfig struct {
+	ServiceName        string   `yaml:"service_name" json:"service_name"`
+	ServiceVersion     string   `yaml:"service_version" json:"service_version"`
+	Endpoint           string   `yaml:"endpoint" json:"endpoint"`
+	BatchTimeout       Duration `yaml:"batch_timeout" json:"batch_timeout"`
+	MaxExportBatchSize int      `yaml:"max_export_batch_size" json:"max_export_batch_size"`
+	// Protocol is grpc or http/protobuf
+	Protocol string `yaml:"protocol" json:"protocol"`
+	// Insecure sends spans in plain text; otherwise TLS is used, verified against Certificate
+	// (or the system roots) and with ClientCertificate and ClientKey for mutual TLS
+	Insecure          bool   `yaml:"insecure" json:"insecure"`
+	Certificate       string `yaml:"certificate" json:"certificate"`
+	ClientCertificate string `yaml:"client_certificate" json:"client_certificate"`
+	ClientKey         string `yaml:"client_key" json:"client_key"`
+	// Sampler is one of the OTEL_TRACES_SAMPLER names in samplers; SamplerArg is the ratio for the *traceidratio ones
+	Sampler    string        `yaml:"sampler" json:"sampler"`
+	SamplerArg float64       `yaml:"sampler_arg" json:"sampler_arg"`
+	Rules      SamplingRules `yaml:"rules" json:"rules"`
+}
+
+// SamplingRules adjust the sampler's decision: traces with an error or a slow root span are
+// kept even when the sampler dropped them, and requests to DropRoutes are never sampled
+type SamplingRules struct {
+	KeepErrors bool `yaml:"keep_errors" json:"keep_errors"`
+	// SlowThreshold keeps traces whose root span takes at least this long; 0 disables it
+	SlowThreshold Duration `yaml:"slow_threshold" json:"slow_threshold"`
+	// DropRoutes are HTTP routes or gRPC methods, e.g. /livez or grpc.health.v1.Health/Check
+	DropRoutes []string `yaml:"drop_routes" json:"drop_routes"`
+}
+
+// samplers are the supported OTEL_TRACES_SAMPLER values
+var samplers = []string{
+	"always_on", "always_off", "traceidratio",
+	"parentbased_always_on", "parentbased_always_off", "parentbased_traceidratio",
+}
+
+// MetricsConfig holds the OpenTelemetry metrics settings. Metrics are always served on /metrics;
+// the OTLP exporter shares the protocol and TLS settings of tracing.
+type MetricsConfig struct {
+	// Exporter is otlp to push metrics to the collector as well, or none
+	Exporter string `yaml:"exporter" json:"exporter"`
+	// Endpoint is the collector for metrics; empty means the tracing endpoint
+	Endpoint string   `yaml:"endpoint" json:"endpoint"`
+	Interval Duration `yaml:"interval" json:"interval"`
+}
+
+// LogConfig holds the logging settings
+type LogConfig struct {
+	Level string `yaml:"level" json:"level"`
+}
+
+// ReservationConfig holds the stock reservation settings
+type ReservationConfig struct {
+	ReaperInterval Duration `yaml:"reaper_interval" json:"reaper_interval"`
+}
+
+// AuthConfig holds the authentication of write endpoints; reads are always public.
+// A request is authenticated by any of the configured API keys, HMAC secret or JWKS.
+type AuthConfig struct {
+	// Mode is required, or disabled to leave write endpoints open (local development only)
+	Mode string `yaml:"mode" json:"mode"`
+	// APIKeys are principal:sha256-hex entries; APIKeysFile holds one entry per line
+	APIKeys     []string `yaml:"api_keys" json:"api_keys"`
+	APIKeysFile string   `yaml:"api_keys_file" json:"api_keys_file"`
+	// JWTSecret verifies HS256/384/512 tokens; JWKSFile is a JSON Web Key Set for RS* and ES* tokens
+	JWTSecret string `yaml:"jwt_secret" json:"jwt_secret"`
+	JWKSFile  string `yaml:"jwks_file" json:"jwks_file"`
+	// JWTIssuer and JWTAudience, when set, must match the iss and aud claims
+	JWTIssuer   string `yaml:"jwt_issuer" json:"jwt_issuer"`
+	JWTAudience string `yaml:"jwt_audience" json:"jwt_audience"`
+}
+
+// Duration is a time.Duration written as "5m" in YAML, environment variables and JSON
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalYAML parses a duration string such as "30s"
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	parsed, err := time.ParseDuration(value.Value)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q", value.Value)
+	}
+	d.Duration = parsed
+	return nil
+}
+
+// MarshalJSON writes the duration as a string such as "30s"
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// redacted replaces secrets in the effective config
+const redacted = "[REDACTED]"
+
+// Default returns the built-in settings, matching what the service used before it was configurable
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Port:            "8080",
+			GRPCPort:        "9090",
+			ShutdownDelay:   Duration{5 * time.Second},
+			ShutdownTimeout: Duration{20 * time.Second},
+		},
+		Database: DatabaseConfig{
+			Host:            "localhost",
+			Port:            "5432",
+			User:            "catalog_user",
+			Password:        "catalog_pass",
+			Name:            "localmart",
+			SSLMode:         "disable",
+			MaxOpenConns:    25,
+			MaxIdleConns:    25,
+			ConnMaxLifetime: Duration{5 * time.Minute},
+		},
+		Tracing: TracingConfig{
+			ServiceName:        "catalog-service",
+			ServiceVersion:     buildVersion(),
+			Endpoint:           "alloy.monitoring.svc.cluster.local:4318",
+			BatchTimeout:       Duration{5 * time.Second},
+			MaxExportBatchSize: 512,
+			Protocol:           "http/protobuf",
+			Insecure:           true,
+			Sampler:            "parentbased_always_on",
+			SamplerArg:         1,
+			Rules: SamplingRules{
+				KeepErrors:    true,
+				SlowThreshold: Duration{time.Second},
+				DropRoutes: []string{
+					"/livez", "/readyz", "/startupz", "/health", "/metrics",
+					"grpc.health.v1.Health/Check", "grpc.health.v1.Health/Watch",
+				},
+			},
+		},
+		Metrics: MetricsConfig{
+			Exporter: "otlp",
+			Interval: Duration{30 * time.Second},
+		},
+		Log: LogConfig{
+			Level: "info",
+		},
+		Reservations: ReservationConfig{
+			ReaperInterval: Duration{30 * time.Second},
+		},
+		Auth: AuthConfig{
+			Mode: "required",
+		},
+	}
+}
+
+// Load builds the config from defaults, the YAML file named by CONFIG_FILE (if set)
+// and environment variables, then validates it
+func Load() (*Config, error) {
+	return load((*Config).validate)
+}
+
+// LoadDatabase builds the config like Load but only validates the database and log
+// settings, for commands such as "catalog migrate" that neither serve nor authenticate
+func LoadDatabase() (*Config, error) {
+	return load(func(c *Config) []string {
+		return append(c.validateDatabase(), c.validateLog()...)
+	})
+}
+
+// load builds the config and checks it with validate
+func load(validate func(*Config) []string) (*Config, error) {
+	cfg := Default()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		// An empty file decodes to io.EOF and simply changes nothing
+		if err := decoder.Decode(&cfg); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+
+	env := envReader{}
+	env.string("PORT", &cfg.Server.Port)
+	env.string("GRPC_PORT", &cfg.Server.GRPCPort)
+	env.duration("SHUTDOWN_DELAY", &cfg.Server.ShutdownDelay)
+	env.duration("SHUTDOWN_TIMEOUT", &cfg.Server.ShutdownTimeout)
+
+	env.string("DB_HOST", &cfg.Database.Host)
+	env.string("DB_PORT", &cfg.Database.Port)
+	env.string("DB_USER", &cfg.Database.User)
+	env.string("DB_PASSWORD", &cfg.Database.Password)
+	env.string("DB_NAME", &cfg.Database.Name)
+	env.string("DB_SSLMODE", &cfg.Database.SSLMode)
+	env.int("DB_MAX_OPEN_CONNS", &cfg.Database.MaxOpenConns)
+	env.int("DB_MAX_IDLE_CONNS", &cfg.Database.MaxIdleConns)
+	env.duration("DB_CONN_MAX_LIFETIME", &cfg.Database.ConnMaxLifetime)
+
+	env.string("OTEL_SERVICE_NAME", &cfg.Tracing.ServiceName)
+	env.string("OTEL_SERVICE_VERSION", &cfg.Tracing.ServiceVersion)
+	env.string("OTEL_EXPORTER_OTLP_ENDPOINT", &cfg.Tracing.Endpoint)
+	env.millis("OTEL_BSP_SCHEDULE_DELAY", &cfg.Tracing.BatchTimeout)
+	env.int("OTEL_BSP_MAX_EXPORT_BATCH_SIZE", &cfg.Tracing.MaxExportBatchSize)
+	env.string("OTEL_EXPORTER_OTLP_PROTOCOL", &cfg.Tracing.Protocol)
+	env.bool("OTEL_EXPORTER_OTLP_INSECURE", &cfg.Tracing.Insecure)
+	env.string("OTEL_EXPORTER_OTLP_CERTIFICATE", &cfg.Tracing.Certificate)
+	env.string("OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE", &cfg.Tracing.ClientCertificate)
+	env.string("OTEL_EXPORTER_OTLP_CLIENT_KEY", &cfg.Tracing.ClientKey)
+	env.string("OTEL_TRACES_SAMPLER", &cfg.Tracing.Sampler)
+	env.float("OTEL_TRACES_SAMPLER_ARG", &cfg.Tracing.SamplerArg)
+	env.bool("TRACES_KEEP_ERRORS", &cfg.Tracing.Rules.KeepErrors)
+	env.duration("TRACES_SLOW_THRESHOLD", &cfg.Tracing.Rules.SlowThreshold)
+	env.list("TRACES_DROP_ROUTES", &cfg.Tracing.Rules.DropRoutes)
+
+	env.string("OTEL_METRICS_EXPORTER", &cfg.Metrics.Exporter)
+	env.string("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT", &cfg.Metrics.Endpoint)
+	env.millis("OTEL_METRIC_EXPORT_INTERVAL", &cfg.Metrics.Interval)
+
+	env.string("LOG_LEVEL", &cfg.Log.Level)
+
+	env.duration("RESERVATION_REAPER_INTERVAL", &cfg.Reservations.ReaperInterval)
+
+	env.string("AUTH_MODE", &cfg.Auth.Mode)
+	env.list("AUTH_API_KEYS", &cfg.Auth.APIKeys)
+	env.string("AUTH_API_KEYS_FILE", &cfg.Auth.APIKeysFile)
+	env.string("AUTH_JWT_SECRET", &cfg.Auth.JWTSecret)
+	env.string("AUTH_JWKS_FILE", &cfg.Auth.JWKSFile)
+	env.string("AUTH_JWT_ISSUER", &cfg.Auth.JWTIssuer)
+	env.string("AUTH_JWT_AUDIENCE", &cfg.Auth.JWTAudience)
+
+	errs := env.errs
+	errs = append(errs, validate(&cfg)...)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid configuration:\n  - %s", strings.Join(errs, "\n  - "))
+	}
+
+	return &cfg, nil
+}
+
+// validate returns one message per invalid setting
+func (c *Config) validate() []string {
+	var errs []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Sprintf(format, args...))
+		}
+	}
+
+	check(validPort(c.Server.Port), "server.port (PORT): %q is not a valid port", c.Server.Port)
+	check(validPort(c.Server.GRPCPort), "server.grpc_port (GRPC_PORT): %q is not a valid port", c.Server.GRPCPort)
+	check(c.Server.Port != c.Server.GRPCPort, "server.grpc_port (GRPC_PORT): must differ from server.port")
+	check(c.Server.ShutdownDelay.Duration >= 0, "server.shutdown_delay (SHUTDOWN_DELAY): must not be negative")
+	check(c.Server.ShutdownTimeout.Duration > 0, "server.shutdown_timeout (SHUTDOWN_TIMEOUT): must be positive")
+
+	errs = append(errs, c.validateDatabase()...)
+
+	check(c.Tracing.ServiceName != "", "tracing.service_name (OTEL_SERVICE_NAME): must not be empty")
+	check(c.Tracing.Endpoint != "", "tracing.endpoint (OTEL_EXPORTER_OTLP_ENDPOINT): must not be empty")
+	check(c.Tracing.BatchTimeout.Duration > 0, "tracing.batch_timeout (OTEL_BSP_SCHEDULE_DELAY): must be positive")
+	check(c.Tracing.MaxExportBatchSize > 0, "tracing.max_export_batch_size (OTEL_BSP_MAX_EXPORT_BATCH_SIZE): must be at least 1")
+	check(c.Tracing.Protocol == "grpc" || c.Tracing.Protocol == "http/protobuf",
+		"tracing.protocol (OTEL_EXPORTER_OTLP_PROTOCOL): %q is not one of grpc, http/protobuf", c.Tracing.Protocol)
+	check((c.Tracing.ClientCertificate == "") == (c.Tracing.ClientKey == ""),
+		"tracing.client_certificate (OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE): must be set together with tracing.client_key (OTEL_EXPORTER_OTLP_CLIENT_KEY)")
+	check(slices.Contains(samplers, c.Tracing.Sampler),
+		"tracing.sampler (OTEL_TRACES_SAMPLER): %q is not one of %s", c.Tracing.Sampler, strings.Join(samplers, ", "))
+	if strings.HasSuffix(c.Tracing.Sampler, "traceidratio") {
+		check(c.Tracing.SamplerArg >= 0 && c.Tracing.SamplerArg <= 1,
+			"tracing.sampler_arg (OTEL_TRACES_SAMPLER_ARG): must be between 0 and 1")
+	}
+	check(c.Tracing.Rules.SlowThreshold.Duration >= 0, "tracing.rules.slow_threshold (TRACES_SLOW_THRESHOLD): must not be negative")
+
+	check(c.Metrics.Exporter == "otlp" || c.Metrics.Exporter == "none",
+		"metrics.exporter (OTEL_METRICS_EXPORTER): %q is not one of otlp, none", c.Metrics.Exporter)
+	check(c.Metrics.Interval.Duration > 0, "metrics.interval (OTEL_METRIC_EXPORT_INTERVAL): must be positive")
+
+	errs = append(errs, c.validateLog()...)
+
+	check(c.Reservations.ReaperInterval.Duration > 0, "reservations.reaper_interval (RESERVATION_REAPER_INTERVAL): must be positive")
+
+	check(c.Auth.Mode == "required" || c.Auth.Mode == "disabled",
+		"auth.mode (AUTH_MODE): %q is not one of required, disabled", c.Auth.Mode)
+	if c.Auth.Mode == "required" {
+		check(len(c.Auth.APIKeys) > 0 || c.Auth.APIKeysFile != "" || c.Auth.JWTSecret != "" || c.Auth.JWKSFile != "",
+			"auth (AUTH_MODE): required needs auth.api_keys (AUTH_API_KEYS), auth.api_keys_file (AUTH_API_KEYS_FILE), "+
+				"auth.jwt_secret (AUTH_JWT_SECRET) or auth.jwks_file (AUTH_JWKS_FILE)")
+	}
+	// A short HMAC secret can be brute-forced from a single token
+	check(c.Auth.JWTSecret == "" || len(c.Auth.JWTSecret) >= 32, "auth.jwt_secret (AUTH_JWT_SECRET): must be at least 32 bytes")
+
+	return errs
+}
+
+// validateDatabase returns one message per invalid database setting
+func (c *Config) validateDatabase() []string {
+	var errs []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Sprintf(format, args...))
+		}
+	}
+
+	check(c.Database.Host != "", "database.host (DB_HOST): must not be empty")
+	check(validPort(c.Database.Port), "database.port (DB_PORT): %q is not a valid port", c.Database.Port)
+	check(c.Database.User != "", "database.user (DB_USER): must not be empty")
+	check(c.Database.Name != "", "database.name (DB_NAME): must not be empty")
+	switch c.Database.SSLMode {
+	case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+	default:
+		check(false, "database.sslmode (DB_SSLMODE): %q is not a valid sslmode", c.Database.SSLMode)
+	}
+	check(c.Database.MaxOpenConns > 0, "database.max_open_conns (DB_MAX_OPEN_CONNS): must be at least 1")
+	// database/sql caps idle connections at max_open_conns itself
+	check(c.Database.MaxIdleConns >= 0, "database.max_idle_conns (DB_MAX_IDLE_CONNS): must not be negative")
+	check(c.Database.ConnMaxLifetime.Duration >= 0, "database.conn_max_lifetime (DB_CONN_MAX_LIFETIME): must not be negative")
+
+	return errs
+}
+
+// validateLog returns a message if the log level is invalid
+func (c *Config) validateLog() []string {
+	if _, err := logrus.ParseLevel(c.Log.Level); err != nil {
+		return []string{fmt.Sprintf("log.level (LOG_LEVEL): %q is not a valid log level", c.Log.Level)}
+	}
+	return nil
+}
+
+// Redacted returns a copy of the config that is safe to log or serve, with secrets replaced
+func (c Config) Redacted() Config {
+	if c.Database.Password != "" {
+		c.Database.Password = redacted
+	}
+	if c.Auth.JWTSecret != "" {
+		c.Auth.JWTSecret = redacted
+	}
+	// Keep the principals, which say who may write, but not the key hashes
+	if len(c.Auth.APIKeys) > 0 {
+		keys := make([]string, len(c.Auth.APIKeys))
+		for i, entry := range c.Auth.APIKeys {
+			principal, _, _ := strings.Cut(entry, ":")
+			keys[i] = principal + ":" + redacted
+		}
+		c.Auth.APIKeys = keys
+	}
+	return c
+}
+
+// buildVersion returns the version the binary was built from: the module version for
+// tagged builds, otherwise the VCS revision, otherwise "dev"
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "dev"
+	}
+	if v := info.Main.Version; v != "" && v != "(devel)" {
+		return v
+	}
+	var revision, modified string
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value
+		}
+	}
+	if revision == "" {
+		return "dev"
+	}
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	if modified == "true" {
+		revision += "-dirty"
+	}
+	return revision
+}
+
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n < 65536
+}
+
+// envReader overrides settings from environment variables, collecting parse errors
+type envReader struct {
+	errs []string
+}
+
+func (e *envReader) string(key string, dest *string) {
+	if value := os.Getenv(key); value != "" {
+		*dest = value
+	}
+}
+
+func (e *envReader) int(key string, dest *int) {
+	if value := os.Getenv(key); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Sprintf("%s: %q is not an integer", key, value))
+			return
+		}
+		*dest = n
+	}
+}
+
+func (e *envReader) bool(key string, dest *bool) {
+	if value := os.Getenv(key); value != "" {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Sprintf("%s: %q is not a boolean", key, value))
+			return
+		}
+		*dest = b
+	}
+}
+
+// list reads a comma-separated list; "," sets an empty list
+func (e *envReader) list(key string, dest *[]string) {
+	if value := os.Getenv(key); value != "" {
+		items := []string{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		*dest = items
+	}
+}
+
+func (e *envReader) float(key string, dest *float64) {
+	if value := os.Getenv(key); value != "" {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Sprintf("%s: %q is not a number", key, value))
+			return
+		}
+		*dest = f
+	}
+}
+
+func (e *envReader) duration(key string, dest *Duration) {
+	if value := os.Getenv(key); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Sprintf("%s: %q is not a duration (e.g. 30s, 5m)", key, value))
+			return
+		}
+		dest.Duration = d
+	}
+}
+
+// millis reads a duration given in milliseconds, the unit the OTEL_BSP_* variables use
+func (e *envReader) millis(key string, dest *Duration) {
+	if value := os.Getenv(key); value != "" {
+		ms, err := strconv.Atoi(value)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Sprintf("%s: %q is not a number of milliseconds", key, value))
+			return
+		}
+		dest.Duration = time.Duration(ms) * time.Millisecond
+	}
+}
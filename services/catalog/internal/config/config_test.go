@@ -0,0 +1,51 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		load func() (*Config, error)
+		env  map[string]string
+		err  string
+	}{
+		{name: "serve without auth", load: Load, err: "auth (AUTH_MODE): required needs"},
+		{name: "serve with auth", load: Load, env: map[string]string{"AUTH_API_KEYS": "a:" + strings.Repeat("0", 64)}},
+		{name: "serve with auth disabled", load: Load, env: map[string]string{"AUTH_MODE": "disabled"}},
+		{name: "short JWT secret", load: Load, env: map[string]string{"AUTH_JWT_SECRET": "short"}, err: "must be at least 32 bytes"},
+		{name: "migrate without auth", load: LoadDatabase},
+		{name: "migrate ignores tracing", load: LoadDatabase, env: map[string]string{"OTEL_TRACES_SAMPLER": "sometimes"}},
+		{name: "migrate checks the database", load: LoadDatabase, env: map[string]string{"DB_PORT": "0"}, err: `database.port (DB_PORT): "0" is not a valid port`},
+		{name: "migrate checks the log level", load: LoadDatabase, env: map[string]string{"LOG_LEVEL": "loud"}, err: "log.level (LOG_LEVEL)"},
+		{name: "unparsable value", load: LoadDatabase, env: map[string]string{"DB_MAX_OPEN_CONNS": "many"}, err: "DB_MAX_OPEN_CONNS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Start from the defaults, whatever the environment running the tests sets
+			for _, name := range []string{"CONFIG_FILE", "AUTH_MODE", "AUTH_API_KEYS", "AUTH_API_KEYS_FILE", "AUTH_JWT_SECRET", "AUTH_JWKS_FILE"} {
+				t.Setenv(name, "")
+			}
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			cfg, err := tt.load()
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("load error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("load error = %v", err)
+			}
+			if cfg.Database.Host == "" {
+				t.Errorf("database config was not loaded: %+v", cfg.Database)
+			}
+		})
+	}
+}
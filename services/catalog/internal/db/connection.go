@@ -4,13 +4,16 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"os"
+	"strconv"
 	"time"
 
+	"catalog-service/internal/config"
 	"catalog-service/internal/logger"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
 )
 
 type Database struct {
@@ -18,38 +21,40 @@ type Database struct {
 }
 
 // Connect establishes a connection to PostgreSQL
-func Connect() (*Database, error) {
-	// Get database configuration from environment variables
-	host := getEnv("DB_HOST", "localhost")
-	port := getEnv("DB_PORT", "5432")
-	user := getEnv("DB_USER", "catalog_user")
-	password := getEnv("DB_PASSWORD", "catalog_pass")
-	dbname := getEnv("DB_NAME", "localmart")
-	sslmode := getEnv("DB_SSLMODE", "disable")
-
+func Connect(cfg config.DatabaseConfig) (*Database, error) {
 	// Build connection string
 	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		host, port, user, password, dbname, sslmode)
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode)
 
 	logger.WithFields(logrus.Fields{
 		"component": "database",
 		"action":    "connect",
-		"host":      host,
-		"port":      port,
-		"database":  dbname,
-		"user":      user,
+		"host":      cfg.Host,
+		"port":      cfg.Port,
+		"database":  cfg.Name,
+		"user":      cfg.User,
 	}).Info("Connecting to database")
 
-	// Open database connection
-	db, err := sql.Open("postgres", connStr)
+	// Open database connection through the instrumented connector, which traces every statement
+	connector, err := pq.NewConnector(connStr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	attrs := []attribute.KeyValue{
+		semconv.DBNamespace(cfg.Name),
+		semconv.ServerAddress(cfg.Host),
+	}
+	if port, err := strconv.Atoi(cfg.Port); err == nil {
+		attrs = append(attrs, semconv.ServerPort(port))
+	}
+	instrumented := newInstrumentedConnector(connector, attrs...)
+	db := sql.OpenDB(instrumented)
+	instrumented.observePool(db)
 
 	// Configure connection pool
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(25)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime.Duration)
 
 	// Test the connection
 	if err := db.Ping(); err != nil {
@@ -60,8 +65,8 @@ func Connect() (*Database, error) {
 		"component": "database",
 		"action":    "connect",
 		"status":    "success",
-		"host":      host,
-		"port":      port,
+		"host":      cfg.Host,
+		"port":      cfg.Port,
 	}).Info("Successfully connected to database")
 
 	return &Database{DB: db}, nil
@@ -90,34 +95,3 @@ func (d *Database) HealthCheck() error {
 
 	return nil
 }
-
-// InitSchema creates the basic products table
-func (d *Database) InitSchema() error {
-	query := `
-	CREATE TABLE IF NOT EXISTS products (
-		id SERIAL PRIMARY KEY,
-		name VARCHAR(255) NOT NULL,
-		description TEXT,
-		price DECIMAL(10,2) NOT NULL,
-		stock_quantity INTEGER DEFAULT 0
-	);`
-
-	if _, err := d.DB.Exec(query); err != nil {
-		return fmt.Errorf("failed to create products table: %w", err)
-	}
-
-	logger.WithFields(logrus.Fields{
-		"component": "database",
-		"action":    "schema_init",
-		"status":    "success",
-	}).Info("Database schema initialized")
-
-	return nil
-}
-
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
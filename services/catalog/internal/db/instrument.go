@@ -0,0 +1,512 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"catalog-service/internal/metrics"
+
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// queryMetrics is shared by every connection of the process
+var queryMetrics = metrics.NewDBQueryMetrics()
+
+// queryKey is the context key of the service query a statement belongs to
+type queryKey struct{}
+
+// query names the service query a statement belongs to, e.g. "get_product" on "products"
+type query struct {
+	name  string
+	table string
+}
+
+// WithQuery labels the statements run with ctx as part of a service query, e.g.
+// WithQuery(ctx, "get_product", "products"). The name is recorded on the statement spans
+// and is the operation label of the query metrics.
+func WithQuery(ctx context.Context, name, table string) context.Context {
+	return context.WithValue(ctx, queryKey{}, query{name: name, table: table})
+}
+
+// instrumentedConnector wraps a driver.Connector so every connection it opens creates spans
+// following the OpenTelemetry database semantic conventions and records the query metrics
+type instrumentedConnector struct {
+	connector driver.Connector
+	tracer    trace.Tracer
+	// attrs are set on every span, e.g. db.system.name and server.address
+	attrs []attribute.KeyValue
+
+	// stats reads the pool statistics, it is set once the *sql.DB exists
+	stats func() sql.DBStats
+	// waitMu guards the pool wait totals seen by the last acquire
+	waitMu       sync.Mutex
+	waitCount    int64
+	waitDuration time.Duration
+}
+
+func newInstrumentedConnector(connector driver.Connector, attrs ...attribute.KeyValue) *instrumentedConnector {
+	return &instrumentedConnector{
+		connector: connector,
+		tracer:    otel.Tracer("catalog-service"),
+		attrs:     append([]attribute.KeyValue{semconv.DBSystemNamePostgreSQL}, attrs...),
+	}
+}
+
+// Connect opens a new connection. database/sql hands out pooled connections without calling
+// the driver, so this span only appears when the pool has to open one; waiting for a pooled
+// connection gets an "acquire" span instead.
+func (c *instrumentedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	ctx, span := c.startSpan(ctx, "connect",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(c.attrs...),
+	)
+	defer span.End()
+
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+	return &instrumentedConn{conn: conn, connector: c}, nil
+}
+
+// startSpan starts a child of the span in ctx. Database work outside of any trace, such as a
+// background job that did not start a span, gets none: each statement would be a trace of its own.
+func (c *instrumentedConnector) startSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	return c.tracer.Start(ctx, name, opts...)
+}
+
+// Driver returns the wrapped driver
+func (c *instrumentedConnector) Driver() driver.Driver {
+	return c.connector.Driver()
+}
+
+// observePool lets the connector read the pool statistics of the DB it was opened with
+func (c *instrumentedConnector) observePool(db *sql.DB) {
+	c.stats = db.Stats
+	stats := db.Stats()
+	c.waitCount, c.waitDuration = stats.WaitCount, stats.WaitDuration
+}
+
+// acquired records an "acquire" span for a pooled connection handed out to ctx if the pool
+// made callers wait for one. database/sql only exposes the total wait of all callers, so the
+// span covers the average wait of the acquires that finished since the last one was recorded.
+func (c *instrumentedConnector) acquired(ctx context.Context) {
+	if c.stats == nil {
+		return
+	}
+	stats := c.stats()
+
+	c.waitMu.Lock()
+	waits := stats.WaitCount - c.waitCount
+	waited := stats.WaitDuration - c.waitDuration
+	c.waitCount, c.waitDuration = stats.WaitCount, stats.WaitDuration
+	c.waitMu.Unlock()
+
+	if waits <= 0 || waited <= 0 {
+		return
+	}
+	wait := waited / time.Duration(waits)
+	now := time.Now()
+
+	_, span := c.startSpan(ctx, "acquire",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(now.Add(-wait)),
+		trace.WithAttributes(c.attrs...),
+		trace.WithAttributes(
+			attribute.Int64("db.client.connection.wait_count", waits),
+			attribute.Int("db.client.connection.max", stats.MaxOpenConnections),
+			attribute.Int("db.client.connection.in_use", stats.InUse),
+		),
+	)
+	span.End(trace.WithTimestamp(now))
+}
+
+// statement is one traced round trip to the database
+type statement struct {
+	span      trace.Span
+	start     time.Time
+	operation string
+	table     string
+}
+
+// startStatement starts the span of a statement. Span names follow the semantic conventions,
+// e.g. "SELECT products"; the metric labels prefer the service query from WithQuery.
+func (c *instrumentedConnector) startStatement(ctx context.Context, sqlText string) (context.Context, *statement) {
+	operation := statementOperation(sqlText)
+	collection := statementCollection(sqlText)
+
+	attrs := append([]attribute.KeyValue{
+		semconv.DBOperationName(operation),
+		semconv.DBQueryText(sanitizeStatement(sqlText)),
+	}, c.attrs...)
+	name := operation
+	if collection != "" {
+		attrs = append(attrs, semconv.DBCollectionName(collection))
+		name += " " + collection
+	}
+
+	stmt := &statement{operation: strings.ToLower(operation), table: collection}
+	if q, ok := ctx.Value(queryKey{}).(query); ok {
+		attrs = append(attrs, attribute.String("catalog.query", q.name))
+		stmt.operation = q.name
+		stmt.table = q.table
+	}
+
+	ctx, stmt.span = c.startSpan(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	stmt.start = time.Now()
+	return ctx, stmt
+}
+
+// end records the outcome of the statement and ends its span
+func (s *statement) end(err error, attrs ...attribute.KeyValue) {
+	failed := err != nil && !errors.Is(err, driver.ErrSkip)
+	if failed {
+		recordError(s.span, err)
+	}
+	s.span.SetAttributes(attrs...)
+
+	queryMetrics.RecordQuery(s.operation, s.table, time.Since(s.start).Seconds(), failed)
+	s.span.End()
+}
+
+// recordError marks a span as failed, with the SQLSTATE code for PostgreSQL errors
+func recordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		span.SetAttributes(semconv.DBResponseStatusCode(string(pqErr.Code)))
+	}
+}
+
+// affectedRows is the attribute for the rows an INSERT, UPDATE or DELETE changed
+func affectedRows(result driver.Result) []attribute.KeyValue {
+	if result == nil {
+		return nil
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return nil
+	}
+	return []attribute.KeyValue{attribute.Int64("db.response.affected_rows", n)}
+}
+
+// namedValuesToValues converts arguments for drivers without the context-aware interfaces
+func namedValuesToValues(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	return values
+}
+
+// instrumentedConn traces the statements and transactions run on one connection
+type instrumentedConn struct {
+	conn      driver.Conn
+	connector *instrumentedConnector
+}
+
+func (c *instrumentedConn) Prepare(sqlText string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), sqlText)
+}
+
+func (c *instrumentedConn) PrepareContext(ctx context.Context, sqlText string) (driver.Stmt, error) {
+	var stmt driver.Stmt
+	var err error
+	if preparer, ok := c.conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, sqlText)
+	} else {
+		stmt, err = c.conn.Prepare(sqlText)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// COPY executes once per row, so it gets one span from prepare to close instead
+	if statementOperation(sqlText) == "COPY" {
+		_, s := c.connector.startStatement(ctx, sqlText)
+		return &copyStmt{stmt: stmt, statement: s}, nil
+	}
+	return &instrumentedStmt{stmt: stmt, sqlText: sqlText, connector: c.connector}, nil
+}
+
+func (c *instrumentedConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *instrumentedConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *instrumentedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	beginner, ok := c.conn.(driver.ConnBeginTx)
+	if !ok {
+		return nil, errors.New("driver does not support BeginTx")
+	}
+
+	_, s := c.connector.startStatement(ctx, "BEGIN")
+	tx, err := beginner.BeginTx(ctx, opts)
+	s.end(err)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedTx{tx: tx, ctx: ctx, connector: c.connector}, nil
+}
+
+func (c *instrumentedConn) QueryContext(ctx context.Context, sqlText string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	ctx, s := c.connector.startStatement(ctx, sqlText)
+	rows, err := queryer.QueryContext(ctx, sqlText, args)
+	if err != nil {
+		s.end(err)
+		return nil, err
+	}
+	return &instrumentedRows{rows: rows, statement: s}, nil
+}
+
+func (c *instrumentedConn) ExecContext(ctx context.Context, sqlText string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	ctx, s := c.connector.startStatement(ctx, sqlText)
+	result, err := execer.ExecContext(ctx, sqlText, args)
+	s.end(err, affectedRows(result)...)
+	return result, err
+}
+
+// Ping is not traced, health checks would drown out real traffic
+func (c *instrumentedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// ResetSession is called with the caller's context when the pool hands out a connection that
+// was used before, right after the caller got it, so it is where pool waits are recorded
+func (c *instrumentedConn) ResetSession(ctx context.Context) error {
+	c.connector.acquired(ctx)
+	if resetter, ok := c.conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *instrumentedConn) IsValid() bool {
+	if validator, ok := c.conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// instrumentedTx traces COMMIT and ROLLBACK as children of the span that began the transaction
+type instrumentedTx struct {
+	tx        driver.Tx
+	ctx       context.Context
+	connector *instrumentedConnector
+}
+
+func (t *instrumentedTx) Commit() error {
+	_, s := t.connector.startStatement(t.ctx, "COMMIT")
+	err := t.tx.Commit()
+	s.end(err)
+	return err
+}
+
+func (t *instrumentedTx) Rollback() error {
+	_, s := t.connector.startStatement(t.ctx, "ROLLBACK")
+	err := t.tx.Rollback()
+	s.end(err)
+	return err
+}
+
+// instrumentedStmt traces each execution of a prepared statement
+type instrumentedStmt struct {
+	stmt      driver.Stmt
+	sqlText   string
+	connector *instrumentedConnector
+}
+
+func (s *instrumentedStmt) Close() error {
+	return s.stmt.Close()
+}
+
+func (s *instrumentedStmt) NumInput() int {
+	return s.stmt.NumInput()
+}
+
+func (s *instrumentedStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.stmt.Exec(args)
+}
+
+func (s *instrumentedStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.stmt.Query(args)
+}
+
+func (s *instrumentedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	ctx, st := s.connector.startStatement(ctx, s.sqlText)
+
+	var result driver.Result
+	var err error
+	if execer, ok := s.stmt.(driver.StmtExecContext); ok {
+		result, err = execer.ExecContext(ctx, args)
+	} else {
+		result, err = s.stmt.Exec(namedValuesToValues(args))
+	}
+	st.end(err, affectedRows(result)...)
+	return result, err
+}
+
+func (s *instrumentedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	ctx, st := s.connector.startStatement(ctx, s.sqlText)
+
+	var rows driver.Rows
+	var err error
+	if queryer, ok := s.stmt.(driver.StmtQueryContext); ok {
+		rows, err = queryer.QueryContext(ctx, args)
+	} else {
+		rows, err = s.stmt.Query(namedValuesToValues(args))
+	}
+	if err != nil {
+		st.end(err)
+		return nil, err
+	}
+	return &instrumentedRows{rows: rows, statement: st}, nil
+}
+
+// copyStmt traces a COPY FROM STDIN as one span covering every row sent
+type copyStmt struct {
+	stmt      driver.Stmt
+	statement *statement
+	rows      int
+	err       error
+}
+
+func (s *copyStmt) NumInput() int {
+	return s.stmt.NumInput()
+}
+
+func (s *copyStmt) Exec(args []driver.Value) (driver.Result, error) {
+	result, err := s.stmt.Exec(args)
+	if len(args) > 0 && err == nil {
+		s.rows++
+	}
+	if err != nil && s.err == nil {
+		s.err = err
+	}
+	return result, err
+}
+
+func (s *copyStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.stmt.Query(args)
+}
+
+func (s *copyStmt) Close() error {
+	err := s.stmt.Close()
+	if s.err != nil {
+		err = s.err
+	}
+	s.statement.end(err, attribute.Int("db.response.copied_rows", s.rows))
+	return err
+}
+
+// instrumentedRows ends the statement span when the rows are closed, so the span covers
+// reading the results and records how many rows were returned
+type instrumentedRows struct {
+	rows      driver.Rows
+	statement *statement
+	returned  int
+	err       error
+}
+
+func (r *instrumentedRows) Columns() []string {
+	return r.rows.Columns()
+}
+
+func (r *instrumentedRows) Next(dest []driver.Value) error {
+	err := r.rows.Next(dest)
+	switch {
+	case err == nil:
+		r.returned++
+	case !errors.Is(err, io.EOF):
+		r.err = err
+	}
+	return err
+}
+
+func (r *instrumentedRows) Close() error {
+	err := r.rows.Close()
+	if r.err != nil {
+		err = r.err
+	}
+	r.statement.end(err, semconv.DBResponseReturnedRows(r.returned))
+	return err
+}
+
+func (r *instrumentedRows) HasNextResultSet() bool {
+	if multi, ok := r.rows.(driver.RowsNextResultSet); ok {
+		return multi.HasNextResultSet()
+	}
+	return false
+}
+
+func (r *instrumentedRows) NextResultSet() error {
+	if multi, ok := r.rows.(driver.RowsNextResultSet); ok {
+		return multi.NextResultSet()
+	}
+	return io.EOF
+}
+
+func (r *instrumentedRows) ColumnTypeScanType(index int) reflect.Type {
+	if typed, ok := r.rows.(driver.RowsColumnTypeScanType); ok {
+		return typed.ColumnTypeScanType(index)
+	}
+	return reflect.TypeOf(new(interface{})).Elem()
+}
+
+func (r *instrumentedRows) ColumnTypeDatabaseTypeName(index int) string {
+	if typed, ok := r.rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return typed.ColumnTypeDatabaseTypeName(index)
+	}
+	return ""
+}
+
+func (r *instrumentedRows) ColumnTypeLength(index int) (int64, bool) {
+	if typed, ok := r.rows.(driver.RowsColumnTypeLength); ok {
+		return typed.ColumnTypeLength(index)
+	}
+	return 0, false
+}
+
+func (r *instrumentedRows) ColumnTypePrecisionScale(index int) (int64, int64, bool) {
+	if typed, ok := r.rows.(driver.RowsColumnTypePrecisionScale); ok {
+		return typed.ColumnTypePrecisionScale(index)
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestAcquiredRecordsPoolWaits(t *testing.T) {
+	tests := []struct {
+		name     string
+		before   sql.DBStats
+		after    sql.DBStats
+		wantSpan bool
+		wantWait time.Duration
+	}{
+		{
+			name:   "no wait",
+			before: sql.DBStats{WaitCount: 3, WaitDuration: time.Second},
+			after:  sql.DBStats{WaitCount: 3, WaitDuration: time.Second},
+		},
+		{
+			name:     "one wait",
+			before:   sql.DBStats{WaitCount: 3, WaitDuration: time.Second},
+			after:    sql.DBStats{WaitCount: 4, WaitDuration: time.Second + 40*time.Millisecond},
+			wantSpan: true,
+			wantWait: 40 * time.Millisecond,
+		},
+		{
+			name:     "concurrent waits are averaged",
+			before:   sql.DBStats{},
+			after:    sql.DBStats{WaitCount: 2, WaitDuration: 100 * time.Millisecond},
+			wantSpan: true,
+			wantWait: 50 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			c := newInstrumentedConnector(nil)
+			c.tracer = provider.Tracer("test")
+			c.waitCount, c.waitDuration = tt.before.WaitCount, tt.before.WaitDuration
+			c.stats = func() sql.DBStats { return tt.after }
+
+			ctx, request := c.tracer.Start(context.Background(), "request")
+			defer request.End()
+			c.acquired(ctx)
+
+			spans := recorder.Ended()
+			if !tt.wantSpan {
+				if len(spans) != 0 {
+					t.Fatalf("recorded %d spans, want none", len(spans))
+				}
+				return
+			}
+			if len(spans) != 1 || spans[0].Name() != "acquire" {
+				t.Fatalf("recorded %d spans, want one acquire span", len(spans))
+			}
+			if wait := spans[0].EndTime().Sub(spans[0].StartTime()); wait != tt.wantWait {
+				t.Errorf("acquire span lasted %v, want %v", wait, tt.wantWait)
+			}
+
+			// The next acquire only sees waits that happened after this one
+			c.acquired(ctx)
+			if len(recorder.Ended()) != 1 {
+				t.Errorf("a second acquire without new waits recorded a span")
+			}
+		})
+	}
+}
+
+func TestStartStatementNeedsParentSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	c := newInstrumentedConnector(nil)
+	c.tracer = provider.Tracer("test")
+
+	// Outside of a trace, e.g. a background job without a span of its own
+	_, s := c.startStatement(context.Background(), "SELECT id FROM products")
+	s.end(nil)
+	if spans := recorder.Ended(); len(spans) != 0 {
+		t.Fatalf("a statement without a parent span recorded %d root spans", len(spans))
+	}
+
+	ctx, job := c.tracer.Start(context.Background(), "job")
+	_, s = c.startStatement(ctx, "SELECT id FROM products")
+	s.end(nil)
+	job.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 || spans[0].Name() != "SELECT products" {
+		t.Fatalf("recorded %d spans, want the statement and its parent", len(spans))
+	}
+	if spans[0].Parent().SpanID() != job.SpanContext().SpanID() {
+		t.Errorf("statement span is not a child of the job span")
+	}
+}
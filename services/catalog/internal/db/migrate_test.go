@@ -0,0 +1,108 @@
+package db
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestReadMigrations(t *testing.T) {
+	file := func(sql string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(sql)}
+	}
+
+	tests := []struct {
+		name     string
+		files    fstest.MapFS
+		versions []int
+		err      string
+	}{
+		{
+			name: "ordered by version",
+			files: fstest.MapFS{
+				"migrations/0010_add_index.up.sql":         file("CREATE INDEX"),
+				"migrations/0002_add_column.up.sql":        file("ALTER TABLE"),
+				"migrations/0002_add_column.down.sql":      file("ALTER TABLE DROP"),
+				"migrations/0001_create_products.up.sql":   file("CREATE TABLE"),
+				"migrations/0001_create_products.down.sql": file("DROP TABLE"),
+			},
+			versions: []int{1, 2, 10},
+		},
+		{
+			name:  "unexpected file",
+			files: fstest.MapFS{"migrations/0001_create.sql": file("CREATE TABLE")},
+			err:   `unexpected migration file "0001_create.sql"`,
+		},
+		{
+			name:  "missing name",
+			files: fstest.MapFS{"migrations/0001.up.sql": file("CREATE TABLE")},
+			err:   "must be named <version>_<name>.up.sql",
+		},
+		{
+			name:  "invalid version",
+			files: fstest.MapFS{"migrations/v1_create.up.sql": file("CREATE TABLE")},
+			err:   "has an invalid version",
+		},
+		{
+			name:  "version zero",
+			files: fstest.MapFS{"migrations/0000_create.up.sql": file("CREATE TABLE")},
+			err:   "has an invalid version",
+		},
+		{
+			name: "version used twice",
+			files: fstest.MapFS{
+				"migrations/0001_create_products.up.sql": file("CREATE TABLE"),
+				"migrations/0001_create_orders.up.sql":   file("CREATE TABLE"),
+			},
+			err: "migration version 1 is used by both",
+		},
+		{
+			name:  "down without up",
+			files: fstest.MapFS{"migrations/0001_create.down.sql": file("DROP TABLE")},
+			err:   "migration 1_create has no up file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := readMigrations(tt.files)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("readMigrations() error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readMigrations() error = %v", err)
+			}
+
+			var versions []int
+			for _, m := range migrations {
+				versions = append(versions, m.Version)
+				if m.Up == "" {
+					t.Errorf("migration %d has no up script", m.Version)
+				}
+			}
+			if !slices.Equal(versions, tt.versions) {
+				t.Errorf("versions = %v, want %v", versions, tt.versions)
+			}
+		})
+	}
+}
+
+// TestEmbeddedMigrations checks the migrations shipped with the service load and have down files
+func TestEmbeddedMigrations(t *testing.T) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations() error = %v", err)
+	}
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Errorf("migration %d_%s: version %d, want %d", m.Version, m.Name, m.Version, i+1)
+		}
+		if m.Down == "" {
+			t.Errorf("migration %d_%s has no down file", m.Version, m.Name)
+		}
+	}
+}
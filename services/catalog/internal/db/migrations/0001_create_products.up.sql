@@ -0,0 +1,9 @@
+-- Initial products table. IF NOT EXISTS keeps this safe for databases that
+-- were created by the old InitSchema before migrations existed.
+CREATE TABLE IF NOT EXISTS products (
+	id SERIAL PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	description TEXT,
+	price DECIMAL(10,2) NOT NULL,
+	stock_quantity INTEGER DEFAULT 0
+);
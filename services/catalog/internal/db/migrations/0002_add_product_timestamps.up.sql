@@ -0,0 +1,4 @@
+-- Server-maintained timestamps. Existing rows get the time of the migration.
+ALTER TABLE products
+	ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
@@ -0,0 +1,22 @@
+-- Categories form a tree through parent_id. A category that still has
+-- sub-categories cannot be deleted (ON DELETE RESTRICT).
+CREATE TABLE IF NOT EXISTS categories (
+	id SERIAL PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	description TEXT NOT NULL DEFAULT '',
+	parent_id INTEGER REFERENCES categories(id) ON DELETE RESTRICT,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	CONSTRAINT categories_parent_name_unique UNIQUE NULLS NOT DISTINCT (parent_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS categories_parent_id_idx ON categories (parent_id);
+
+-- Many-to-many link between products and categories
+CREATE TABLE IF NOT EXISTS product_categories (
+	product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+	category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+	PRIMARY KEY (product_id, category_id)
+);
+
+CREATE INDEX IF NOT EXISTS product_categories_category_id_idx ON product_categories (category_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS stock_reservation_items;
+DROP TABLE IF EXISTS stock_reservations;
+ALTER TABLE products DROP COLUMN IF EXISTS reserved_quantity;
@@ -0,0 +1,26 @@
+-- stock_quantity is the stock on hand; reserved_quantity is the part of it held
+-- by active reservations. Available stock is stock_quantity - reserved_quantity.
+ALTER TABLE products
+	ADD COLUMN IF NOT EXISTS reserved_quantity INTEGER NOT NULL DEFAULT 0
+	CONSTRAINT products_reserved_quantity_non_negative CHECK (reserved_quantity >= 0);
+
+-- A reservation holds stock for one checkout until it is committed, released or expires
+CREATE TABLE IF NOT EXISTS stock_reservations (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	status VARCHAR(16) NOT NULL DEFAULT 'active'
+		CHECK (status IN ('active', 'committed', 'released', 'expired')),
+	expires_at TIMESTAMPTZ NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Lets the reaper find expired holds without scanning finished reservations
+CREATE INDEX IF NOT EXISTS stock_reservations_active_expires_at_idx
+	ON stock_reservations (expires_at) WHERE status = 'active';
+
+CREATE TABLE IF NOT EXISTS stock_reservation_items (
+	reservation_id UUID NOT NULL REFERENCES stock_reservations(id) ON DELETE CASCADE,
+	product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+	quantity INTEGER NOT NULL CHECK (quantity > 0),
+	PRIMARY KEY (reservation_id, product_id)
+);
@@ -0,0 +1,3 @@
+-- Row version for optimistic concurrency control. Every write to a product
+-- increments it, and it is exposed to clients as the ETag.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
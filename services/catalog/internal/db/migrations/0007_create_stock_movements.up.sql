@@ -0,0 +1,13 @@
+-- Append-only ledger of every stock change. stock_after is the product's
+-- stock_quantity right after the movement was applied.
+CREATE TABLE IF NOT EXISTS stock_movements (
+	id BIGSERIAL PRIMARY KEY,
+	product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+	delta INTEGER NOT NULL CHECK (delta <> 0),
+	reason VARCHAR(16) NOT NULL CHECK (reason IN ('restock', 'sale', 'damage', 'correction')),
+	note TEXT NOT NULL DEFAULT '',
+	stock_after INTEGER NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS stock_movements_product_id_idx ON stock_movements (product_id, id DESC);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS products_external_key_key;
+
+ALTER TABLE products DROP COLUMN IF EXISTS external_key;
@@ -0,0 +1,5 @@
+-- Caller-supplied key that bulk imports upsert on, e.g. a SKU from an upstream system.
+-- NULL for products created through the API; unique when set.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS external_key VARCHAR(100);
+
+CREATE UNIQUE INDEX IF NOT EXISTS products_external_key_key ON products (external_key);
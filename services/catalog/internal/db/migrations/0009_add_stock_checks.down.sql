@@ -0,0 +1,3 @@
+ALTER TABLE products
+	DROP CONSTRAINT IF EXISTS products_stock_covers_reserved,
+	DROP CONSTRAINT IF EXISTS products_stock_quantity_non_negative;
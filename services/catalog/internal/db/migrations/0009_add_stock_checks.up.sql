@@ -0,0 +1,17 @@
+-- Stock on hand always covers what active reservations hold, so committing a
+-- reservation can never drive stock_quantity negative.
+-- Rows that already break the rule (including negative stock, since reserved_quantity
+-- is never negative) are raised to their reserved quantity first, with a correction
+-- in the ledger so stock_after stays consistent.
+INSERT INTO stock_movements (product_id, delta, reason, note, stock_after)
+SELECT id, reserved_quantity - stock_quantity, 'correction', 'stock raised to the reserved quantity by migration 0009', reserved_quantity
+FROM products
+WHERE stock_quantity < reserved_quantity;
+
+UPDATE products
+SET stock_quantity = reserved_quantity, version = version + 1, updated_at = NOW()
+WHERE stock_quantity < reserved_quantity;
+
+ALTER TABLE products
+	ADD CONSTRAINT products_stock_quantity_non_negative CHECK (stock_quantity >= 0),
+	ADD CONSTRAINT products_stock_covers_reserved CHECK (stock_quantity >= reserved_quantity);
@@ -0,0 +1,167 @@
+package db
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// collectionPattern finds the table a statement works on
+var collectionPattern = regexp.MustCompile(`(?i)\b(?:FROM|INTO|UPDATE|JOIN|TABLE|COPY)\s+(?:ONLY\s+|IF\s+(?:NOT\s+)?EXISTS\s+)?([A-Za-z_][A-Za-z0-9_.]*)`)
+
+// statementOperation returns the SQL command of a statement, e.g. "SELECT". A statement
+// starting with WITH is labelled with the command that follows its common table expressions.
+func statementOperation(query string) string {
+	query = strings.TrimLeft(query, " \t\r\n(")
+	operation := leadingWord(query)
+	if operation == "WITH" {
+		if main := cteMainOperation(query[len(operation):]); main != "" {
+			return main
+		}
+	}
+	return operation
+}
+
+// leadingWord returns the upper-cased letters at the start of s
+func leadingWord(s string) string {
+	end := strings.IndexFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if end == -1 {
+		end = len(s)
+	}
+	return strings.ToUpper(s[:end])
+}
+
+// cteMainOperation returns the first SELECT, INSERT, UPDATE, DELETE or MERGE outside the
+// parentheses of the common table expressions that start query, or "" if there is none
+func cteMainOperation(query string) string {
+	depth := 0
+	for i := 0; i < len(query); i++ {
+		switch c := query[i]; {
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == '\'' || c == '"':
+			// Skip quoted strings and identifiers, doubled quotes escape themselves
+			for i++; i < len(query) && query[i] != c; i++ {
+			}
+		case depth == 0 && isLetter(c) && (i == 0 || !isIdentifierEnd(query[:i])):
+			word := leadingWord(query[i:])
+			switch word {
+			case "SELECT", "INSERT", "UPDATE", "DELETE", "MERGE":
+				return word
+			}
+			i += len(word) - 1
+		}
+	}
+	return ""
+}
+
+// statementCollection returns the first table a statement names, or "" if it names none.
+// Function calls such as FROM unnest($1) are skipped.
+func statementCollection(query string) string {
+	for _, match := range collectionPattern.FindAllStringSubmatchIndex(query, -1) {
+		if strings.HasPrefix(query[match[1]:], "(") {
+			continue
+		}
+		return strings.ToLower(query[match[2]:match[3]])
+	}
+	return ""
+}
+
+// sanitizeStatement replaces string and numeric literals with ? and collapses whitespace,
+// so statements can be recorded on spans without leaking values. Bind parameters ($1)
+// are kept, their values are never recorded.
+func sanitizeStatement(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+
+	space := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			space = true
+			continue
+		case c == '-' && i+1 < len(query) && query[i+1] == '-':
+			// Line comment
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+			space = true
+			continue
+		}
+
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+
+		switch {
+		case c == '\'':
+			// String literal, '' is an escaped quote
+			for i++; i < len(query); i++ {
+				if query[i] == '\'' {
+					if i+1 < len(query) && query[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			b.WriteByte('?')
+		case c == '$' && i+1 < len(query) && isDigit(query[i+1]):
+			// Bind parameter
+			b.WriteByte(c)
+			for i+1 < len(query) && isDigit(query[i+1]) {
+				i++
+				b.WriteByte(query[i])
+			}
+		case c == '$':
+			// Dollar-quoted string such as $$...$$ or $tag$...$tag$
+			end := strings.IndexByte(query[i+1:], '$')
+			if end == -1 {
+				b.WriteByte(c)
+				continue
+			}
+			tag := query[i : i+end+2]
+			closing := strings.Index(query[i+len(tag):], tag)
+			if closing == -1 {
+				b.WriteString(query[i:])
+				return b.String()
+			}
+			i += len(tag) + closing + len(tag) - 1
+			b.WriteByte('?')
+		case isDigit(c) && !isIdentifierEnd(b.String()):
+			// Numeric literal
+			for i+1 < len(query) && (isDigit(query[i+1]) || query[i+1] == '.') {
+				i++
+			}
+			b.WriteByte('?')
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// isIdentifierEnd reports whether s ends inside an identifier, so a digit continues it (e.g. col1)
+func isIdentifierEnd(s string) bool {
+	if s == "" {
+		return false
+	}
+	c := s[len(s)-1]
+	return c == '_' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
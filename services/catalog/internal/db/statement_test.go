@@ -0,0 +1,138 @@
+package db
+
+import "testing"
+
+func TestSanitizeStatement(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "bind parameters are kept",
+			query: "SELECT * FROM products WHERE id = $1 AND price > $12",
+			want:  "SELECT * FROM products WHERE id = $1 AND price > $12",
+		},
+		{
+			name:  "string literal",
+			query: "SELECT * FROM products WHERE name = 'Widget'",
+			want:  "SELECT * FROM products WHERE name = ?",
+		},
+		{
+			name:  "escaped quote inside a string",
+			query: "UPDATE products SET name = 'O''Brien''s' WHERE id = $1",
+			want:  "UPDATE products SET name = ? WHERE id = $1",
+		},
+		{
+			name:  "numeric literals",
+			query: "SELECT * FROM products WHERE price > 19.99 LIMIT 10",
+			want:  "SELECT * FROM products WHERE price > ? LIMIT ?",
+		},
+		{
+			name:  "digits in identifiers are kept",
+			query: "SELECT col1, t2.x_3 FROM t2",
+			want:  "SELECT col1, t2.x_3 FROM t2",
+		},
+		{
+			name:  "dollar-quoted string",
+			query: "SELECT $$secret 'value'$$, 1",
+			want:  "SELECT ?, ?",
+		},
+		{
+			name:  "tagged dollar-quoted string",
+			query: "DO $body$ BEGIN RAISE 'x'; END $body$",
+			want:  "DO ?",
+		},
+		{
+			name:  "unterminated dollar quote",
+			query: "SELECT $tag$ never closed",
+			want:  "SELECT $tag$ never closed",
+		},
+		{
+			name:  "whitespace is collapsed",
+			query: "\n\tSELECT  id,\n\t\tname\r\n\tFROM   products\n",
+			want:  "SELECT id, name FROM products",
+		},
+		{
+			name:  "line comments are dropped",
+			query: "SELECT id -- the key 'abc'\nFROM products -- trailing",
+			want:  "SELECT id FROM products",
+		},
+		{
+			name:  "casts and operators are kept",
+			query: "SELECT created_at::timestamptz FROM products WHERE stock_quantity - reserved_quantity >= $1",
+			want:  "SELECT created_at::timestamptz FROM products WHERE stock_quantity - reserved_quantity >= $1",
+		},
+		{
+			name:  "empty",
+			query: "   ",
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeStatement(tt.query); got != tt.want {
+				t.Errorf("sanitizeStatement(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatementOperation(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: "SELECT 1", want: "SELECT"},
+		{query: "\n  insert into products (name) values ($1)", want: "INSERT"},
+		{query: "(SELECT id FROM products) UNION (SELECT id FROM categories)", want: "SELECT"},
+		{query: "COPY products (name) FROM STDIN", want: "COPY"},
+		{query: "BEGIN", want: "BEGIN"},
+		{
+			query: "WITH updated AS (UPDATE products SET price = $1 RETURNING id) SELECT COUNT(*) FROM updated",
+			want:  "SELECT",
+		},
+		{
+			query: "WITH RECURSIVE tree(id) AS (SELECT id FROM categories UNION ALL SELECT c.id FROM categories c JOIN tree ON c.parent_id = tree.id) DELETE FROM product_categories WHERE category_id IN (SELECT id FROM tree)",
+			want:  "DELETE",
+		},
+		{
+			query: `WITH a AS (SELECT 'update' AS word), "select" AS MATERIALIZED (SELECT 1) INSERT INTO log SELECT * FROM a`,
+			want:  "INSERT",
+		},
+		{query: "WITH my_update AS (SELECT 1) UPDATE products SET name = $1", want: "UPDATE"},
+		{query: "WITH", want: "WITH"},
+		{query: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := statementOperation(tt.query); got != tt.want {
+				t.Errorf("statementOperation(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatementCollection(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: "SELECT * FROM products WHERE id = $1", want: "products"},
+		{query: "INSERT INTO Stock_Movements (product_id) VALUES ($1)", want: "stock_movements"},
+		{query: "UPDATE ONLY products SET name = $1", want: "products"},
+		{query: "SELECT * FROM unnest($1::int[]) AS ids JOIN products p ON p.id = ids", want: "products"},
+		{query: "CREATE TABLE IF NOT EXISTS schema_migrations (version int)", want: "schema_migrations"},
+		{query: "SELECT 1", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := statementCollection(tt.query); got != tt.want {
+				t.Errorf("statementCollection(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"catalog-service/internal/logger"
+	"catalog-service/internal/models"
+	catalogv1 "catalog-service/internal/pb/catalog/v1"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Validation failure reasons reported by ValidateProducts
+const (
+	reasonNotFound          = "not_found"
+	reasonPriceChanged      = "price_changed"
+	reasonInsufficientStock = "insufficient_stock"
+)
+
+// priceTolerance absorbs float rounding when comparing prices in whole cents
+const priceTolerance = 0.005
+
+// catalogService implements catalogv1.CatalogServiceServer on top of models.ProductService
+type catalogService struct {
+	catalogv1.UnimplementedCatalogServiceServer
+
+	productService *models.ProductService
+}
+
+func newCatalogService(productService *models.ProductService) *catalogService {
+	return &catalogService{productService: productService}
+}
+
+// toProto converts a Product to its protobuf form
+func toProto(p *models.Product) *catalogv1.Product {
+	return &catalogv1.Product{
+		Id:                int32(p.ID),
+		Name:              p.Name,
+		Description:       p.Description,
+		Price:             p.Price,
+		StockQuantity:     int32(p.StockQty),
+		ReservedQuantity:  int32(p.ReservedQty),
+		AvailableQuantity: int32(p.AvailableQty()),
+		Version:           int32(p.Version),
+		CreatedAt:         timestamppb.New(p.CreatedAt),
+		UpdatedAt:         timestamppb.New(p.UpdatedAt),
+	}
+}
+
+// internalError logs an unexpected error and hides its details from the caller
+func internalError(ctx context.Context, err error, action string) error {
+	logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
+		"component": "grpc",
+		"action":    action,
+	}).Error("Request failed")
+	return status.Error(codes.Internal, "internal error")
+}
+
+// serviceError maps an error of the models package to a gRPC status by its kind.
+// Unexpected errors are logged and reported as internal.
+func serviceError(ctx context.Context, err error, action string) error {
+	switch {
+	case errors.Is(err, models.ErrValidation):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, models.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, models.ErrVersionMismatch):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, models.ErrConflict):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, models.ErrUnavailable):
+		return status.Error(codes.Unavailable, "service unavailable")
+	}
+	return internalError(ctx, err, action)
+}
+
+// GetProduct returns a single product
+func (s *catalogService) GetProduct(ctx context.Context, req *catalogv1.GetProductRequest) (*catalogv1.GetProductResponse, error) {
+	if req.GetId() < 1 {
+		return nil, status.Error(codes.InvalidArgument, "id must be positive")
+	}
+
+	product, err := s.productService.GetProduct(ctx, int(req.GetId()))
+	if err != nil {
+		if errors.Is(err, models.ErrProductNotFound) {
+			return nil, status.Errorf(codes.NotFound, "product %d not found", req.GetId())
+		}
+		return nil, serviceError(ctx, err, "get_product")
+	}
+
+	return &catalogv1.GetProductResponse{Product: toProto(product)}, nil
+}
+
+// lookupIDs validates a list of product IDs for a batch call
+func lookupIDs(ids []int32) ([]int, error) {
+	if len(ids) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one id is required")
+	}
+	if len(ids) > models.MaxBatchGetProducts {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d ids per request", models.MaxBatchGetProducts)
+	}
+
+	lookup := make([]int, len(ids))
+	for i, id := range ids {
+		if id < 1 {
+			return nil, status.Errorf(codes.InvalidArgument, "ids[%d] must be positive", i)
+		}
+		lookup[i] = int(id)
+	}
+	return lookup, nil
+}
+
+// BatchGetProducts returns several products in request order
+func (s *catalogService) BatchGetProducts(ctx context.Context, req *catalogv1.BatchGetProductsRequest) (*catalogv1.BatchGetProductsResponse, error) {
+	ids, err := lookupIDs(req.GetIds())
+	if err != nil {
+		return nil, err
+	}
+
+	products, notFound, err := s.productService.GetProductsByIDs(ctx, ids)
+	if err != nil {
+		return nil, serviceError(ctx, err, "batch_get_products")
+	}
+
+	resp := &catalogv1.BatchGetProductsResponse{
+		Products: make([]*catalogv1.Product, 0, len(products)),
+		NotFound: make([]int32, 0, len(notFound)),
+	}
+	for i := range products {
+		resp.Products = append(resp.Products, toProto(&products[i]))
+	}
+	for _, id := range notFound {
+		resp.NotFound = append(resp.NotFound, int32(id))
+	}
+	return resp, nil
+}
+
+// ValidateProducts checks existence, price and available stock for each item.
+// Items for the same product are checked against their combined quantity.
+func (s *catalogService) ValidateProducts(ctx context.Context, req *catalogv1.ValidateProductsRequest) (*catalogv1.ValidateProductsResponse, error) {
+	items := req.GetItems()
+	if len(items) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one item is required")
+	}
+	if len(items) > models.MaxBatchGetProducts {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d items per request", models.MaxBatchGetProducts)
+	}
+
+	ids := make([]int, len(items))
+	wanted := make(map[int]int, len(items))
+	for i, item := range items {
+		if item.GetProductId() < 1 {
+			return nil, status.Errorf(codes.InvalidArgument, "items[%d].product_id must be positive", i)
+		}
+		if item.GetQuantity() < 1 {
+			return nil, status.Errorf(codes.InvalidArgument, "items[%d].quantity must be positive", i)
+		}
+		ids[i] = int(item.GetProductId())
+		wanted[ids[i]] += int(item.GetQuantity())
+	}
+
+	products, _, err := s.productService.GetProductsByIDs(ctx, ids)
+	if err != nil {
+		return nil, serviceError(ctx, err, "validate_products")
+	}
+
+	byID := make(map[int]*models.Product, len(products))
+	for i := range products {
+		byID[products[i].ID] = &products[i]
+	}
+
+	resp := &catalogv1.ValidateProductsResponse{Valid: true}
+	for _, item := range items {
+		result := &catalogv1.ValidationResult{ProductId: item.GetProductId()}
+
+		product, ok := byID[int(item.GetProductId())]
+		if ok {
+			result.Exists = true
+			result.CurrentPrice = product.Price
+			result.AvailableQuantity = int32(product.AvailableQty())
+			result.PriceMatches = item.ExpectedPrice == nil || math.Abs(item.GetExpectedPrice()-product.Price) < priceTolerance
+			result.InStock = product.AvailableQty() >= wanted[product.ID]
+		}
+
+		switch {
+		case !result.Exists:
+			result.Reason = reasonNotFound
+		case !result.PriceMatches:
+			result.Reason = reasonPriceChanged
+		case !result.InStock:
+			result.Reason = reasonInsufficientStock
+		}
+		if result.Reason != "" {
+			resp.Valid = false
+		}
+
+		resp.Results = append(resp.Results, result)
+	}
+
+	return resp, nil
+}
+
+// listProductsFilter builds the listing filter from a ListProducts request
+func listProductsFilter(req *catalogv1.ListProductsRequest) (models.ProductFilter, error) {
+	var filter models.ProductFilter
+
+	if req.CategoryId != nil {
+		categoryID := int(req.GetCategoryId())
+		filter.CategoryID = &categoryID
+	}
+	if req.MinPrice != nil {
+		minPrice := req.GetMinPrice()
+		filter.MinPrice = &minPrice
+	}
+	if req.MaxPrice != nil {
+		maxPrice := req.GetMaxPrice()
+		filter.MaxPrice = &maxPrice
+	}
+	if req.InStock != nil {
+		inStock := req.GetInStock()
+		filter.InStock = &inStock
+	}
+	filter.NameContains = req.GetNameContains()
+
+	var err error
+	if filter.Sort, err = models.ParseProductSort(req.GetSort(), req.GetOrder()); err != nil {
+		return filter, err
+	}
+	return filter, filter.Validate()
+}
+
+// ListProducts pages through products with keyset pagination
+func (s *catalogService) ListProducts(ctx context.Context, req *catalogv1.ListProductsRequest) (*catalogv1.ListProductsResponse, error) {
+	pageSize := int(req.GetPageSize())
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 50
+	}
+
+	filter, err := listProductsFilter(req)
+	if err != nil {
+		return nil, serviceError(ctx, err, "list_products")
+	}
+
+	// Fetch one extra row to find out whether there is a next page
+	page := models.ProductPage{Limit: pageSize + 1}
+	if req.GetPageToken() != "" {
+		cursor, err := models.DecodeProductCursor(req.GetPageToken())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+
+		// The token carries its sort order; an explicit sort must agree with it
+		cursorSort, _ := cursor.ProductSort()
+		if req.GetSort() == "" && req.GetOrder() == "" {
+			filter.Sort = cursorSort
+		} else if cursorSort != filter.Sort {
+			return nil, status.Errorf(codes.InvalidArgument, "page_token was created for sort %s, not %s", cursorSort, filter.Sort)
+		}
+		page.After = cursor
+	}
+
+	products, err := s.productService.GetAllProducts(ctx, filter, page)
+	if err != nil {
+		return nil, serviceError(ctx, err, "list_products")
+	}
+
+	resp := &catalogv1.ListProductsResponse{}
+	if len(products) > pageSize {
+		products = products[:pageSize]
+		resp.NextPageToken = models.NewProductCursor(products[pageSize-1], filter.Sort).Encode()
+	}
+
+	resp.Products = make([]*catalogv1.Product, 0, len(products))
+	for i := range products {
+		resp.Products = append(resp.Products, toProto(&products[i]))
+	}
+	return resp, nil
+}
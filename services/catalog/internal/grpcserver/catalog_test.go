@@ -0,0 +1,171 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+
+	"catalog-service/internal/models"
+	catalogv1 "catalog-service/internal/pb/catalog/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestLookupIDs(t *testing.T) {
+	tooMany := make([]int32, models.MaxBatchGetProducts+1)
+	for i := range tooMany {
+		tooMany[i] = int32(i + 1)
+	}
+
+	tests := []struct {
+		name string
+		ids  []int32
+		want []int
+		code codes.Code
+	}{
+		{name: "in request order", ids: []int32{3, 1, 2}, want: []int{3, 1, 2}},
+		{name: "duplicates are kept", ids: []int32{5, 5}, want: []int{5, 5}},
+		{name: "limit", ids: tooMany[:models.MaxBatchGetProducts], want: nil},
+		{name: "none", ids: nil, code: codes.InvalidArgument},
+		{name: "too many", ids: tooMany, code: codes.InvalidArgument},
+		{name: "zero", ids: []int32{1, 0}, code: codes.InvalidArgument},
+		{name: "negative", ids: []int32{-1}, code: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, err := lookupIDs(tt.ids)
+			if tt.code != codes.OK {
+				if status.Code(err) != tt.code {
+					t.Fatalf("lookupIDs() error = %v, want code %s", err, tt.code)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("lookupIDs() error = %v", err)
+			}
+			if tt.want != nil && !slices.Equal(ids, tt.want) {
+				t.Errorf("lookupIDs() = %v, want %v", ids, tt.want)
+			}
+			if len(ids) != len(tt.ids) {
+				t.Errorf("lookupIDs() returned %d ids, want %d", len(ids), len(tt.ids))
+			}
+		})
+	}
+}
+
+func TestListProductsFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *catalogv1.ListProductsRequest
+		check   func(t *testing.T, filter models.ProductFilter)
+		invalid bool
+	}{
+		{
+			name: "defaults",
+			req:  &catalogv1.ListProductsRequest{},
+			check: func(t *testing.T, filter models.ProductFilter) {
+				if filter.CategoryID != nil || filter.MinPrice != nil || filter.MaxPrice != nil || filter.InStock != nil {
+					t.Errorf("unset fields became filters: %+v", filter)
+				}
+				if filter.Sort != (models.ProductSort{Field: "id"}) {
+					t.Errorf("sort = %+v, want id ascending", filter.Sort)
+				}
+			},
+		},
+		{
+			name: "every filter",
+			req: &catalogv1.ListProductsRequest{
+				CategoryId:   proto.Int32(4),
+				MinPrice:     proto.Float64(5),
+				MaxPrice:     proto.Float64(10),
+				InStock:      proto.Bool(false),
+				NameContains: "  widget ",
+				Sort:         "price",
+				Order:        "desc",
+			},
+			check: func(t *testing.T, filter models.ProductFilter) {
+				if filter.CategoryID == nil || *filter.CategoryID != 4 {
+					t.Errorf("category id = %v, want 4", filter.CategoryID)
+				}
+				if filter.MinPrice == nil || *filter.MinPrice != 5 || filter.MaxPrice == nil || *filter.MaxPrice != 10 {
+					t.Errorf("price range = %v..%v, want 5..10", filter.MinPrice, filter.MaxPrice)
+				}
+				if filter.InStock == nil || *filter.InStock {
+					t.Errorf("in stock = %v, want false", filter.InStock)
+				}
+				if filter.NameContains != "widget" {
+					t.Errorf("name contains = %q, want it trimmed", filter.NameContains)
+				}
+				if filter.Sort != (models.ProductSort{Field: "price", Desc: true}) {
+					t.Errorf("sort = %+v, want price descending", filter.Sort)
+				}
+			},
+		},
+		{
+			name: "newest sorts descending by default",
+			req:  &catalogv1.ListProductsRequest{Sort: "newest"},
+			check: func(t *testing.T, filter models.ProductFilter) {
+				if !filter.Sort.Desc {
+					t.Errorf("sort = %+v, want descending", filter.Sort)
+				}
+			},
+		},
+		{name: "unknown sort", req: &catalogv1.ListProductsRequest{Sort: "reserved_quantity"}, invalid: true},
+		{name: "unknown order", req: &catalogv1.ListProductsRequest{Order: "up"}, invalid: true},
+		{name: "negative price", req: &catalogv1.ListProductsRequest{MinPrice: proto.Float64(-1)}, invalid: true},
+		{name: "NaN price", req: &catalogv1.ListProductsRequest{MaxPrice: proto.Float64(math.NaN())}, invalid: true},
+		{
+			name:    "inverted price range",
+			req:     &catalogv1.ListProductsRequest{MinPrice: proto.Float64(10), MaxPrice: proto.Float64(5)},
+			invalid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := listProductsFilter(tt.req)
+			if tt.invalid {
+				if !errors.Is(err, models.ErrValidation) {
+					t.Fatalf("listProductsFilter() error = %v, want a validation error", err)
+				}
+				if code := status.Code(serviceError(context.Background(), err, "list_products")); code != codes.InvalidArgument {
+					t.Errorf("status code = %s, want %s", code, codes.InvalidArgument)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("listProductsFilter() error = %v", err)
+			}
+			tt.check(t, filter)
+		})
+	}
+}
+
+func TestServiceError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{name: "validation", err: models.NewValidationError("name", models.CodeRequired, "is required"), code: codes.InvalidArgument},
+		{name: "not found", err: models.ErrProductNotFound, code: codes.NotFound},
+		{name: "version mismatch", err: models.ErrVersionMismatch, code: codes.Aborted},
+		{name: "insufficient stock", err: &models.InsufficientStockError{ProductID: 1, Requested: 5, Available: 2}, code: codes.FailedPrecondition},
+		{name: "unavailable", err: fmt.Errorf("get product: %w", models.ErrUnavailable), code: codes.Unavailable},
+		{name: "unexpected", err: errors.New("boom"), code: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := status.Code(serviceError(context.Background(), tt.err, "test")); code != tt.code {
+				t.Errorf("serviceError(%v) code = %s, want %s", tt.err, code, tt.code)
+			}
+		})
+	}
+}
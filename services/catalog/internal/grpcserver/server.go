@@ -0,0 +1,221 @@
+package grpcserver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"catalog-service/internal/logger"
+	"catalog-service/internal/metrics"
+	"catalog-service/internal/models"
+	catalogv1 "catalog-service/internal/pb/catalog/v1"
+	"catalog-service/internal/requestid"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Server represents the gRPC server
+type Server struct {
+	server  *grpc.Server
+	health  *health.Server
+	metrics *metrics.GRPCMetrics
+}
+
+// NewServer creates a gRPC server exposing the catalog service
+func NewServer(database *sql.DB) *Server {
+	s := &Server{
+		health:  health.NewServer(),
+		metrics: metrics.NewGRPCMetrics(),
+	}
+
+	// Interceptors run in this order after the otelgrpc stats handler has created the span:
+	// 1. recovery (outermost, so a panic anywhere fails only its call), 2. request id,
+	// 3. metrics, 4. logging (can use trace context and the request id)
+	s.server = grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			s.recoveryInterceptor(),
+			requestIDInterceptor(),
+			s.metricsInterceptor(),
+			s.loggingInterceptor(),
+		),
+	)
+
+	productService := models.NewProductService(database)
+	catalogv1.RegisterCatalogServiceServer(s.server, newCatalogService(productService))
+	healthpb.RegisterHealthServer(s.server, s.health)
+
+	return s
+}
+
+// callScope carries what the interceptors learn about a call out to recoveryInterceptor, which
+// runs before them and cannot see the context they pass on
+type callScope struct {
+	requestID string
+}
+
+// callScopeKey is the context key of the callScope
+type callScopeKey struct{}
+
+// recoveryInterceptor is the gRPC counterpart of gin's Recovery: a panicking call is logged with
+// its stack and request id, counted as an Internal error and answered with codes.Internal,
+// instead of crashing the process
+func (s *Server) recoveryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		scope := &callScope{}
+		start := time.Now()
+
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			logger.FromContext(ctx).WithFields(logrus.Fields{
+				"component":  "grpc",
+				"action":     "recover",
+				"method":     info.FullMethod,
+				"request_id": scope.requestID,
+				"panic":      fmt.Sprint(recovered),
+				"stack":      string(debug.Stack()),
+			}).Error("gRPC handler panicked")
+
+			// The metrics interceptor never saw the call return, so it is counted here
+			err = status.Error(codes.Internal, "internal error")
+			if info.FullMethod != healthpb.Health_Check_FullMethodName {
+				s.metrics.RecordRequest(info.FullMethod, codes.Internal.String(), time.Since(start).Seconds())
+			}
+			resp = nil
+		}()
+
+		return handler(context.WithValue(ctx, callScopeKey{}, scope), req)
+	}
+}
+
+// requestIDInterceptor is the gRPC counterpart of the HTTP request id middleware: it accepts
+// the caller's x-request-id metadata or generates one, and returns it as response header metadata
+func requestIDInterceptor() grpc.UnaryServerInterceptor {
+	key := strings.ToLower(requestid.Header)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var id string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(key); len(values) > 0 {
+				id = values[0]
+			}
+		}
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+		// Failing to send the header only loses the echo, not the request
+		_ = grpc.SetHeader(ctx, metadata.Pairs(key, id))
+
+		if scope, ok := ctx.Value(callScopeKey{}).(*callScope); ok {
+			scope.requestID = id
+		}
+		ctx = requestid.NewContext(ctx, id)
+		ctx = logger.ContextWithFields(ctx, logrus.Fields{"request_id": id})
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("catalog.request_id", id))
+		return handler(ctx, req)
+	}
+}
+
+// metricsInterceptor collects gRPC metrics for Prometheus
+func (s *Server) metricsInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		// Health checks would drown out real traffic
+		if info.FullMethod == healthpb.Health_Check_FullMethodName {
+			return handler(ctx, req)
+		}
+
+		s.metrics.IncInFlight()
+		defer s.metrics.DecInFlight()
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		s.metrics.RecordRequest(info.FullMethod, status.Code(err).String(), time.Since(start).Seconds())
+		return resp, err
+	}
+}
+
+// loggingInterceptor logs gRPC requests with structured JSON and trace correlation
+func (s *Server) loggingInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+
+		// Every log line of the call carries its method
+		ctx = logger.ContextWithFields(ctx, logrus.Fields{"grpc_method": info.FullMethod})
+		resp, err := handler(ctx, req)
+
+		// Skip logging for health checks to reduce noise
+		if info.FullMethod == healthpb.Health_Check_FullMethodName {
+			return resp, err
+		}
+
+		// FromContext adds the trace information for correlation
+		logger.FromContext(ctx).WithFields(logrus.Fields{
+			"component":   "grpc",
+			"method":      info.FullMethod,
+			"code":        status.Code(err).String(),
+			"duration_ms": time.Since(start).Milliseconds(),
+		}).Info("gRPC request processed")
+		return resp, err
+	}
+}
+
+// Start listens on the given port and serves until Stop is called
+func (s *Server) Start(port string) error {
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return err
+	}
+
+	s.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+
+	logger.WithFields(logrus.Fields{
+		"component": "grpc",
+		"action":    "start",
+		"port":      port,
+	}).Info("Starting gRPC server")
+
+	return s.server.Serve(listener)
+}
+
+// BeginShutdown reports NOT_SERVING on the health service while RPCs are still served
+func (s *Server) BeginShutdown() {
+	s.health.Shutdown()
+}
+
+// Shutdown stops accepting new RPCs and waits for in-flight ones to finish.
+// RPCs still running when ctx expires are cancelled.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.health.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		<-done
+		return ctx.Err()
+	}
+}
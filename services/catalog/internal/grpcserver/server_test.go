@@ -0,0 +1,89 @@
+package grpcserver
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// chain runs handler through the interceptors like grpc.ChainUnaryInterceptor does
+func chain(interceptors []grpc.UnaryServerInterceptor, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) grpc.UnaryHandler {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+			return interceptor(ctx, req, info, next)
+		}
+	}
+	return handler
+}
+
+func TestRecoveryInterceptor(t *testing.T) {
+	database, err := sql.Open("postgres", "host=127.0.0.1 port=1 sslmode=disable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+	s := NewServer(database)
+	interceptors := []grpc.UnaryServerInterceptor{
+		s.recoveryInterceptor(), requestIDInterceptor(), s.metricsInterceptor(), s.loggingInterceptor(),
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		handler grpc.UnaryHandler
+		code    codes.Code
+	}{
+		{
+			name:    "panic",
+			method:  "/catalog.v1.CatalogService/Panics",
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) { panic("boom") },
+			code:    codes.Internal,
+		},
+		{
+			name:   "nil pointer",
+			method: "/catalog.v1.CatalogService/Dereferences",
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				var product *struct{ Name string }
+				return product.Name, nil
+			},
+			code: codes.Internal,
+		},
+		{
+			name:   "error",
+			method: "/catalog.v1.CatalogService/Fails",
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, status.Error(codes.NotFound, "no")
+			},
+			code: codes.NotFound,
+		},
+		{
+			name:    "success",
+			method:  "/catalog.v1.CatalogService/Works",
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil },
+			code:    codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := &grpc.UnaryServerInfo{FullMethod: tt.method}
+			_, err := chain(interceptors, info, tt.handler)(context.Background(), nil)
+			if status.Code(err) != tt.code {
+				t.Fatalf("call error = %v, want code %s", err, tt.code)
+			}
+
+			if got := testutil.ToFloat64(s.metrics.RequestsTotal.WithLabelValues(tt.method, tt.code.String())); got != 1 {
+				t.Errorf("%s calls counted with code %s = %v, want 1", tt.method, tt.code, got)
+			}
+			if got := testutil.ToFloat64(s.metrics.RequestsInFlight); got != 0 {
+				t.Errorf("in-flight requests = %v, want 0", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	"catalog-service/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler handles operational endpoints for the service itself
+type AdminHandler struct {
+	cfg *config.Config
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(cfg *config.Config) *AdminHandler {
+	return &AdminHandler{
+		cfg: cfg,
+	}
+}
+
+// GetConfig handles GET /admin/config
+// It returns the effective configuration the service started with, with secrets redacted.
+func (h *AdminHandler) GetConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"data": h.cfg.Redacted(),
+	})
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"catalog-service/internal/auth"
+	"catalog-service/internal/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequireAuth rejects requests the authenticator does not accept with a 401 problem, and
+// records the caller of the others in the request context, its logs and the request span.
+// A nil authenticator means authentication is disabled and lets every request through.
+func RequireAuth(authenticator auth.Authenticator) gin.HandlerFunc {
+	if authenticator == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		span := trace.SpanFromContext(ctx)
+
+		principal, err := authenticator.Authenticate(c.Request)
+		if err != nil {
+			// The reason stays in the logs; the client only learns whether credentials were missing
+			logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
+				"component": "auth",
+				"action":    "authenticate",
+				"client_ip": c.ClientIP(),
+			}).Warn("Request not authenticated")
+
+			if errors.Is(err, auth.ErrNoCredentials) {
+				span.SetAttributes(attribute.String("catalog.auth.result", "missing"))
+				c.Header("WWW-Authenticate", `Bearer realm="catalog"`)
+				abortWithError(c, newStatusError(http.StatusUnauthorized, "unauthenticated",
+					"Authentication required: send an API key in the "+auth.APIKeyHeader+" header or a bearer token"))
+				return
+			}
+			span.SetAttributes(attribute.String("catalog.auth.result", "invalid"))
+			c.Header("WWW-Authenticate", `Bearer realm="catalog", error="invalid_token"`)
+			abortWithError(c, newStatusError(http.StatusUnauthorized, "invalid_credentials", "The API key or token is invalid or expired"))
+			return
+		}
+
+		span.SetAttributes(
+			attribute.String("catalog.auth.result", "ok"),
+			attribute.String("catalog.auth.method", principal.Method),
+			attribute.String("enduser.id", principal.ID),
+		)
+		ctx = auth.NewContext(ctx, principal)
+		ctx = logger.ContextWithFields(ctx, logrus.Fields{
+			"principal":   principal.ID,
+			"auth_method": principal.Method,
+		})
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
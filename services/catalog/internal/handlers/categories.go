@@ -0,0 +1,420 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"catalog-service/internal/logger"
+	"catalog-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// CategoryHandler handles category-related HTTP requests
+type CategoryHandler struct {
+	categoryService *models.CategoryService
+	productService  *models.ProductService
+}
+
+// NewCategoryHandler creates a new category handler
+func NewCategoryHandler(categoryService *models.CategoryService, productService *models.ProductService) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService: categoryService,
+		productService:  productService,
+	}
+}
+
+// rejectedCategoryChange reports whether a category error is an expected rejection,
+// such as an unknown id or a duplicate name, rather than a failure of the service
+func rejectedCategoryChange(err error) bool {
+	return errors.Is(err, models.ErrNotFound) || errors.Is(err, models.ErrConflict) || errors.Is(err, models.ErrValidation)
+}
+
+// parseCategoryID parses the :id path parameter, writing a 400 response if it is invalid
+func parseCategoryID(c *gin.Context, action string) (int, bool) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component": "handler",
+			"action":    action,
+			"id_param":  idStr,
+		}).Error("Invalid category ID")
+
+		abortWithError(c, invalidIDError("id"))
+		return 0, false
+	}
+	return id, true
+}
+
+// GetCategories handles GET /api/v1/categories
+// Returns the category tree, or a flat list with ?flat=true
+func (h *CategoryHandler) GetCategories(c *gin.Context) {
+	categories, err := h.categoryService.GetAllCategories(c.Request.Context())
+	if err != nil {
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component": "handler",
+			"action":    "get_categories",
+		}).Error("Failed to retrieve categories")
+
+		abortWithError(c, err)
+		return
+	}
+
+	if flat, _ := strconv.ParseBool(c.Query("flat")); flat {
+		responses := make([]models.CategoryResponse, 0, len(categories))
+		for _, category := range categories {
+			responses = append(responses, category.ToResponse())
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"data":  responses,
+			"count": len(responses),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  models.BuildCategoryTree(categories),
+		"count": len(categories),
+	})
+}
+
+// GetCategory handles GET /api/v1/categories/:id
+func (h *CategoryHandler) GetCategory(c *gin.Context) {
+	id, ok := parseCategoryID(c, "get_category")
+	if !ok {
+		return
+	}
+
+	category, err := h.categoryService.GetCategory(c.Request.Context(), id)
+	if err != nil {
+		if rejectedCategoryChange(err) {
+			logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+				"component":   "handler",
+				"action":      "get_category",
+				"category_id": id,
+			}).Warn("Category request rejected")
+
+			abortWithError(c, err)
+			return
+		}
+
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component":   "handler",
+			"action":      "get_category",
+			"category_id": id,
+		}).Error("Failed to retrieve category")
+
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": category.ToResponse(),
+	})
+}
+
+// CreateCategory handles POST /api/v1/categories
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	var req models.CategoryCreateRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component": "handler",
+			"action":    "create_category",
+		}).Error("Invalid request data")
+
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	category, err := h.categoryService.CreateCategory(c.Request.Context(), req)
+	if err != nil {
+		if rejectedCategoryChange(err) {
+			logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+				"component": "handler",
+				"action":    "create_category",
+				"name":      req.Name,
+			}).Warn("Category request rejected")
+
+			abortWithError(c, err)
+			return
+		}
+
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component": "handler",
+			"action":    "create_category",
+			"name":      req.Name,
+		}).Error("Failed to create category")
+
+		abortWithError(c, err)
+		return
+	}
+
+	logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
+		"component":   "handler",
+		"action":      "create_category",
+		"category_id": category.ID,
+		"name":        category.Name,
+	}).Info("Category created successfully")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": category.ToResponse(),
+	})
+}
+
+// UpdateCategory handles PUT /api/v1/categories/:id
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	id, ok := parseCategoryID(c, "update_category")
+	if !ok {
+		return
+	}
+
+	var req models.CategoryUpdateRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component":   "handler",
+			"action":      "update_category",
+			"category_id": id,
+		}).Error("Invalid request data")
+
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	category, err := h.categoryService.UpdateCategory(c.Request.Context(), id, req)
+	if err != nil {
+		if rejectedCategoryChange(err) {
+			logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+				"component":   "handler",
+				"action":      "update_category",
+				"category_id": id,
+			}).Warn("Category request rejected")
+
+			abortWithError(c, err)
+			return
+		}
+
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component":   "handler",
+			"action":      "update_category",
+			"category_id": id,
+		}).Error("Failed to update category")
+
+		abortWithError(c, err)
+		return
+	}
+
+	logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
+		"component":   "handler",
+		"action":      "update_category",
+		"category_id": category.ID,
+		"name":        category.Name,
+	}).Info("Category updated successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": category.ToResponse(),
+	})
+}
+
+// DeleteCategory handles DELETE /api/v1/categories/:id
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	id, ok := parseCategoryID(c, "delete_category")
+	if !ok {
+		return
+	}
+
+	if err := h.categoryService.DeleteCategory(c.Request.Context(), id); err != nil {
+		if rejectedCategoryChange(err) {
+			logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+				"component":   "handler",
+				"action":      "delete_category",
+				"category_id": id,
+			}).Warn("Category request rejected")
+
+			abortWithError(c, err)
+			return
+		}
+
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component":   "handler",
+			"action":      "delete_category",
+			"category_id": id,
+		}).Error("Failed to delete category")
+
+		abortWithError(c, err)
+		return
+	}
+
+	logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
+		"component":   "handler",
+		"action":      "delete_category",
+		"category_id": id,
+	}).Info("Category deleted successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":    gin.H{"id": id},
+		"message": "Category deleted successfully",
+	})
+}
+
+// GetCategoryProducts handles GET /api/v1/categories/:id/products
+// Products in sub-categories are included.
+func (h *CategoryHandler) GetCategoryProducts(c *gin.Context) {
+	id, ok := parseCategoryID(c, "get_category_products")
+	if !ok {
+		return
+	}
+
+	// Parse query parameters for pagination
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	offset := (page - 1) * limit
+
+	// Make sure the category exists so an unknown id is a 404 rather than an empty list
+	if _, err := h.categoryService.GetCategory(c.Request.Context(), id); err != nil {
+		if rejectedCategoryChange(err) {
+			abortWithError(c, err)
+			return
+		}
+
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component":   "handler",
+			"action":      "get_category_products",
+			"category_id": id,
+		}).Error("Failed to retrieve category")
+
+		abortWithError(c, err)
+		return
+	}
+
+	filter := models.ProductFilter{CategoryID: &id}
+	products, err := h.productService.GetAllProducts(c.Request.Context(), filter, models.ProductPage{Offset: offset, Limit: limit})
+	if err != nil {
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component":   "handler",
+			"action":      "get_category_products",
+			"category_id": id,
+		}).Error("Failed to retrieve category products")
+
+		abortWithError(c, err)
+		return
+	}
+
+	responses := make([]models.ProductResponse, 0, len(products))
+	for _, product := range products {
+		responses = append(responses, product.ToResponse())
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  responses,
+		"page":  page,
+		"limit": limit,
+		"count": len(responses),
+	})
+}
+
+// AddCategoryProducts handles POST /api/v1/categories/:id/products
+func (h *CategoryHandler) AddCategoryProducts(c *gin.Context) {
+	id, ok := parseCategoryID(c, "add_category_products")
+	if !ok {
+		return
+	}
+
+	var req models.CategoryProductsRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component":   "handler",
+			"action":      "add_category_products",
+			"category_id": id,
+		}).Error("Invalid request data")
+
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	if err := h.categoryService.AddProducts(c.Request.Context(), id, req.ProductIDs); err != nil {
+		if rejectedCategoryChange(err) {
+			logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+				"component":   "handler",
+				"action":      "add_category_products",
+				"category_id": id,
+			}).Warn("Category request rejected")
+
+			abortWithError(c, err)
+			return
+		}
+
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component":   "handler",
+			"action":      "add_category_products",
+			"category_id": id,
+		}).Error("Failed to add products to category")
+
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":    gin.H{"category_id": id, "product_ids": req.ProductIDs},
+		"message": "Products added to category",
+	})
+}
+
+// RemoveCategoryProduct handles DELETE /api/v1/categories/:id/products/:product_id
+func (h *CategoryHandler) RemoveCategoryProduct(c *gin.Context) {
+	id, ok := parseCategoryID(c, "remove_category_product")
+	if !ok {
+		return
+	}
+
+	productIDStr := c.Param("product_id")
+	productID, err := strconv.Atoi(productIDStr)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component": "handler",
+			"action":    "remove_category_product",
+			"id_param":  productIDStr,
+		}).Error("Invalid product ID")
+
+		abortWithError(c, invalidIDError("id"))
+		return
+	}
+
+	if err := h.categoryService.RemoveProduct(c.Request.Context(), id, productID); err != nil {
+		if rejectedCategoryChange(err) {
+			abortWithError(c, err)
+			return
+		}
+
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component":   "handler",
+			"action":      "remove_category_product",
+			"category_id": id,
+			"product_id":  productID,
+		}).Error("Failed to remove product from category")
+
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":    gin.H{"category_id": id, "product_id": productID},
+		"message": "Product removed from category",
+	})
+}
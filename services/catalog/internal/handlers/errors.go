@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"catalog-service/internal/models"
+	"catalog-service/internal/requestid"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// problemContentType is the media type of error responses (RFC 7807)
+const problemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details object, the body of every error response.
+// Code is a stable, machine-readable reason; Errors lists the invalid fields of a 400.
+type Problem struct {
+	Type      string              `json:"type"`
+	Title     string              `json:"title"`
+	Status    int                 `json:"status"`
+	Detail    string              `json:"detail,omitempty"`
+	Instance  string              `json:"instance,omitempty"`
+	Code      string              `json:"code"`
+	RequestID string              `json:"request_id,omitempty"`
+	Errors    []models.FieldError `json:"errors,omitempty"`
+	Details   any                 `json:"details,omitempty"`
+}
+
+// statusError is an error of the HTTP layer itself, such as an unsupported media type,
+// that is not a domain error of the models package
+type statusError struct {
+	status  int
+	code    string
+	detail  string
+	details any
+}
+
+func (e *statusError) Error() string { return e.detail }
+
+// newStatusError returns an error that ErrorHandler renders with the given status and code
+func newStatusError(status int, code, detail string) *statusError {
+	return &statusError{status: status, code: code, detail: detail}
+}
+
+func init() {
+	// Report invalid fields by their JSON name rather than the Go struct field name
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	}
+}
+
+// ErrorHandler renders the error a handler aborted with as an application/problem+json
+// response. It is registered last, so it runs right after the handler returns.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		last := c.Errors.Last()
+		if last == nil || c.Writer.Written() {
+			return
+		}
+		problem := problemFor(last.Err)
+		writeProblem(c, problem)
+
+		// The tracing middleware marks the request span failed when errors remain;
+		// a client error is not a failure of the service
+		if problem.Status < http.StatusInternalServerError {
+			c.Errors = c.Errors[:0]
+		}
+	}
+}
+
+// Recovered renders a panic the recovery middleware caught as a 500 problem
+func Recovered(c *gin.Context, _ any) {
+	writeProblem(c, problemFor(errors.New("panic")))
+	c.Abort()
+}
+
+// NotFound renders requests to unknown routes as a 404 problem
+func NotFound(c *gin.Context) {
+	writeProblem(c, newProblem(http.StatusNotFound, "route_not_found", "No route matches "+c.Request.URL.Path))
+}
+
+// abortWithError stops the request; ErrorHandler writes err as the response.
+// Errors that match none of the models error kinds become a 500 without their details.
+func abortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// newProblem returns a problem with the standard title of its status
+func newProblem(status int, code, detail string) Problem {
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// problemFor maps an error to its problem: the error kinds of the models package decide the status
+func problemFor(err error) Problem {
+	var (
+		httpErr       *statusError
+		validationErr *models.ValidationError
+		stockErr      *models.InsufficientStockError
+	)
+	switch {
+	case errors.As(err, &httpErr):
+		problem := newProblem(httpErr.status, httpErr.code, httpErr.detail)
+		problem.Details = httpErr.details
+		return problem
+	case errors.As(err, &validationErr):
+		problem := newProblem(http.StatusBadRequest, "validation_failed", "The request has invalid fields")
+		problem.Errors = validationErr.Fields
+		return problem
+	case errors.Is(err, models.ErrValidation):
+		return newProblem(http.StatusBadRequest, "validation_failed", capitalize(err.Error()))
+	case errors.Is(err, models.ErrVersionMismatch):
+		return newProblem(http.StatusPreconditionFailed, "precondition_failed", "Product has been modified since it was last read; fetch it again and retry")
+	case errors.Is(err, models.ErrReservationExpired):
+		return newProblem(http.StatusGone, "reservation_expired", "Reservation expired")
+	case errors.As(err, &stockErr):
+		problem := newProblem(http.StatusConflict, "insufficient_stock", "Insufficient stock")
+		details := gin.H{
+			"product_id": stockErr.ProductID,
+			"requested":  stockErr.Requested,
+			"available":  stockErr.Available,
+		}
+		if stockErr.Field != "" {
+			details["field"] = stockErr.Field
+		}
+		problem.Details = details
+		return problem
+	case errors.Is(err, models.ErrNotFound):
+		return newProblem(http.StatusNotFound, "not_found", capitalize(err.Error()))
+	case errors.Is(err, models.ErrConflict):
+		return newProblem(http.StatusConflict, "conflict", capitalize(err.Error()))
+	case errors.Is(err, models.ErrUnavailable):
+		return newProblem(http.StatusServiceUnavailable, "unavailable", "The service is temporarily unavailable; retry later")
+	default:
+		// Internal errors may carry SQL or connection details; the logs have them, the client does not
+		return newProblem(http.StatusInternalServerError, "internal", "An unexpected error occurred; quote the request id when reporting it")
+	}
+}
+
+// writeProblem writes a problem response, adding the request path and the request id
+// so support can match a user's report to the logs
+func writeProblem(c *gin.Context, problem Problem) {
+	problem.Instance = c.Request.URL.Path
+	problem.RequestID = requestid.FromContext(c.Request.Context())
+
+	if problem.Status == http.StatusServiceUnavailable {
+		c.Header("Retry-After", "5")
+	}
+	c.Render(problem.Status, problemRender{problem})
+}
+
+// problemRender renders a problem as JSON with the problem+json content type
+type problemRender struct {
+	problem Problem
+}
+
+func (r problemRender) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	return json.NewEncoder(w).Encode(r.problem)
+}
+
+func (r problemRender) WriteContentType(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", problemContentType)
+}
+
+// capitalize upper-cases the first letter of an error message for use as a problem detail
+func capitalize(message string) string {
+	if message == "" {
+		return message
+	}
+	return strings.ToUpper(message[:1]) + message[1:]
+}
+
+// invalidIDError reports a path or query parameter that is not a numeric ID
+func invalidIDError(param string) error {
+	return models.NewValidationError(param, models.CodeInvalid, "must be an integer ID")
+}
+
+// bindingError turns a request body binding failure into a ValidationError, with one
+// entry per field the binding rules rejected
+func bindingError(err error) error {
+	var fieldErrs validator.ValidationErrors
+	if errors.As(err, &fieldErrs) {
+		validationErr := &models.ValidationError{}
+		for _, fe := range fieldErrs {
+			validationErr.Fields = append(validationErr.Fields, fieldError(fe))
+		}
+		return validationErr
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return models.NewValidationError(typeErr.Field, "invalid_type", "must be "+jsonTypeName(typeErr.Type.Kind().String()))
+	}
+	if errors.Is(err, io.EOF) {
+		return models.NewValidationError("body", models.CodeRequired, "request body is required")
+	}
+	return models.NewValidationError("body", "malformed", "request body is not valid JSON")
+}
+
+// fieldError describes a failed binding rule
+func fieldError(fe validator.FieldError) models.FieldError {
+	// The namespace starts with the struct name: ReservationCreateRequest.items[0].quantity
+	field := fe.Namespace()
+	if _, rest, ok := strings.Cut(field, "."); ok {
+		field = rest
+	}
+
+	code, message := models.CodeInvalid, "is invalid"
+	unit := ""
+	switch fe.Kind() {
+	case reflect.String:
+		unit = " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		unit = " items"
+	}
+
+	switch fe.Tag() {
+	case "required":
+		code, message = models.CodeRequired, "is required"
+	case "oneof":
+		code, message = "invalid_choice", "must be one of "+strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "max", "lte":
+		code, message = models.CodeOutOfRange, fmt.Sprintf("must be at most %s%s", fe.Param(), unit)
+		if unit == " characters" {
+			code = models.CodeTooLong
+		}
+	case "min", "gte":
+		code, message = models.CodeOutOfRange, fmt.Sprintf("must be at least %s%s", fe.Param(), unit)
+		if unit == " characters" {
+			code = "too_short"
+		}
+	case "gt":
+		code, message = models.CodeOutOfRange, "must be greater than "+fe.Param()
+	case "lt":
+		code, message = models.CodeOutOfRange, "must be less than "+fe.Param()
+	}
+
+	return models.FieldError{Field: field, Code: code, Message: message}
+}
+
+// jsonTypeName describes a Go kind as the JSON value a client should send
+func jsonTypeName(kind string) string {
+	switch kind {
+	case "int", "int32", "int64", "uint", "uint32", "uint64":
+		return "an integer"
+	case "float32", "float64":
+		return "a number"
+	case "string":
+		return "a string"
+	case "bool":
+		return "a boolean"
+	case "slice", "array":
+		return "an array"
+	case "map", "struct":
+		return "an object"
+	}
+	return "a " + kind
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// productETag returns the strong ETag for a product version, e.g. "7"
+func productETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// parseIfMatch parses an If-Match header into the product versions it accepts.
+// "*" matches any version and is returned as an empty list. Weak ETags (W/"7")
+// never match, because If-Match uses strong comparison.
+func parseIfMatch(header string) ([]int, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	var versions []int
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if strings.HasPrefix(tag, "W/") {
+			continue
+		}
+		if len(tag) < 2 || !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+			return nil, fmt.Errorf("malformed entity tag %s", tag)
+		}
+
+		version, err := strconv.Atoi(tag[1 : len(tag)-1])
+		if err != nil {
+			// A well-formed tag we never issued cannot match any version
+			version = -1
+		}
+		versions = append(versions, version)
+	}
+
+	if len(versions) == 0 {
+		// Only weak tags were sent, so nothing can match
+		versions = []int{-1}
+	}
+	return versions, nil
+}
+
+// etagMatches reports whether an If-None-Match header matches the given ETag.
+// If-None-Match uses weak comparison, so W/"7" matches "7".
+func etagMatches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		versions []int
+		wantErr  bool
+	}{
+		{name: "absent", header: "", versions: nil},
+		{name: "any version", header: "*", versions: nil},
+		{name: "any version with spaces", header: "  *  ", versions: nil},
+		{name: "single tag", header: `"7"`, versions: []int{7}},
+		{name: "several tags", header: `"7", "8" ,"9"`, versions: []int{7, 8, 9}},
+		{name: "weak tag never matches", header: `W/"7"`, versions: []int{-1}},
+		{name: "weak tags are skipped", header: `W/"7", "8"`, versions: []int{8}},
+		{name: "tag we never issued", header: `"abc"`, versions: []int{-1}},
+		{name: "empty tag", header: `""`, versions: []int{-1}},
+		{name: "unquoted tag", header: `7`, wantErr: true},
+		{name: "half quoted tag", header: `"7`, wantErr: true},
+		{name: "lone quote", header: `"`, wantErr: true},
+		{name: "empty list entry", header: `"7",`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			versions, err := parseIfMatch(tt.header)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseIfMatch(%q) = %v, want an error", tt.header, versions)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseIfMatch(%q) error = %v", tt.header, err)
+			}
+			if !slices.Equal(versions, tt.versions) {
+				t.Errorf("parseIfMatch(%q) = %v, want %v", tt.header, versions, tt.versions)
+			}
+		})
+	}
+}
+
+func TestParseIfMatchAcceptsProductETag(t *testing.T) {
+	for _, version := range []int{1, 7, 1234} {
+		versions, err := parseIfMatch(productETag(version))
+		if err != nil || !slices.Equal(versions, []int{version}) {
+			t.Errorf("parseIfMatch(productETag(%d)) = %v, %v", version, versions, err)
+		}
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		etag   string
+		want   bool
+	}{
+		{name: "same tag", header: `"7"`, etag: `"7"`, want: true},
+		{name: "weak comparison", header: `W/"7"`, etag: `"7"`, want: true},
+		{name: "any", header: "*", etag: `"7"`, want: true},
+		{name: "one of several", header: `"6", "7"`, etag: `"7"`, want: true},
+		{name: "other version", header: `"6"`, etag: `"7"`, want: false},
+		{name: "unquoted", header: `7`, etag: `"7"`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := etagMatches(tt.header, tt.etag); got != tt.want {
+				t.Errorf("etagMatches(%q, %q) = %v, want %v", tt.header, tt.etag, got, tt.want)
+			}
+		})
+	}
+}
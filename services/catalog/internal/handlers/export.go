@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"catalog-service/internal/logger"
+	"catalog-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// exportStatusTrailer is the trailer that tells whether an export is complete. A failure after
+// the status was sent also ends the body with an error marker in the export's format.
+const exportStatusTrailer = "X-Export-Status"
+
+// exportWriter writes products in one export format
+type exportWriter interface {
+	begin() error
+	write(product models.ProductResponse) error
+	end() error
+	// fail ends an export that broke off with a marker no reader takes for a complete file
+	fail(exported int) error
+}
+
+// exportFailure is the message of the error marker
+func exportFailure(exported int) string {
+	return "export failed after " + strconv.Itoa(exported) + " products"
+}
+
+// csvExportColumns is the header line of a CSV export
+var csvExportColumns = []string{
+	"id", "external_key", "name", "description", "price", "stock_quantity",
+	"reserved_quantity", "available_quantity", "version", "created_at", "updated_at",
+}
+
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func (e *csvExportWriter) begin() error {
+	return e.w.Write(csvExportColumns)
+}
+
+func (e *csvExportWriter) write(p models.ProductResponse) error {
+	var externalKey string
+	if p.ExternalKey != nil {
+		externalKey = *p.ExternalKey
+	}
+	return e.w.Write([]string{
+		strconv.Itoa(p.ID),
+		externalKey,
+		p.Name,
+		p.Description,
+		strconv.FormatFloat(p.Price, 'f', 2, 64),
+		strconv.Itoa(p.StockQty),
+		strconv.Itoa(p.ReservedQty),
+		strconv.Itoa(p.AvailableQty),
+		strconv.Itoa(p.Version),
+		p.CreatedAt.UTC().Format(time.RFC3339),
+		p.UpdatedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+func (e *csvExportWriter) end() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// fail adds a one-field record, which readers expecting every record to have all columns reject
+func (e *csvExportWriter) fail(exported int) error {
+	if err := e.w.Write([]string{"# " + exportFailure(exported)}); err != nil {
+		return err
+	}
+	return e.end()
+}
+
+// ndjsonExportWriter writes one JSON object per line
+type ndjsonExportWriter struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonExportWriter) begin() error { return nil }
+
+func (e *ndjsonExportWriter) write(p models.ProductResponse) error {
+	return e.enc.Encode(p)
+}
+
+func (e *ndjsonExportWriter) end() error { return nil }
+
+func (e *ndjsonExportWriter) fail(exported int) error {
+	return e.enc.Encode(gin.H{"error": exportFailure(exported), "exported": exported})
+}
+
+// jsonExportWriter writes a single JSON array without holding it in memory
+type jsonExportWriter struct {
+	w     io.Writer
+	enc   *json.Encoder
+	count int
+}
+
+func (e *jsonExportWriter) begin() error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonExportWriter) write(p models.ProductResponse) error {
+	if e.count > 0 {
+		if _, err := io.WriteString(e.w, ","); err != nil {
+			return err
+		}
+	}
+	e.count++
+	return e.enc.Encode(p)
+}
+
+func (e *jsonExportWriter) end() error {
+	_, err := io.WriteString(e.w, "]\n")
+	return err
+}
+
+// fail leaves the array open, so the document does not parse
+func (e *jsonExportWriter) fail(exported int) error {
+	_, err := io.WriteString(e.w, "\n")
+	return err
+}
+
+// exportFormats maps ?format= to the content type and file extension of the export
+var exportFormats = map[string]struct {
+	contentType string
+	extension   string
+}{
+	"csv":    {"text/csv; charset=utf-8", "csv"},
+	"ndjson": {"application/x-ndjson", "ndjson"},
+	"json":   {"application/json; charset=utf-8", "json"},
+}
+
+// acceptsGzip reports whether the client accepts a gzip-encoded response
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			return strings.ReplaceAll(strings.TrimSpace(params), " ", "") != "q=0"
+		}
+	}
+	return false
+}
+
+// ExportProducts handles GET /api/v1/products/export?format=csv|ndjson|json
+// It accepts the same filters and sort order as GET /api/v1/products and streams every
+// matching product. The response is gzip-compressed when the client sends Accept-Encoding: gzip.
+func (h *ProductHandler) ExportProducts(c *gin.Context) {
+	format := c.DefaultQuery("format", "ndjson")
+	spec, ok := exportFormats[format]
+	if !ok {
+		abortWithError(c, models.NewValidationError("format", "invalid_choice", "must be one of csv, ndjson, json"))
+		return
+	}
+
+	filter, err := parseProductFilter(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	gzipped := acceptsGzip(c.GetHeader("Accept-Encoding"))
+
+	// Nothing is sent until the export cursor is open and the first batch is read, so a failing
+	// export still gets an error response; begin sends the status and the start of the file
+	var (
+		writer   exportWriter
+		buffered *bufio.Writer
+		gz       *gzip.Writer
+		started  bool
+	)
+	begin := func() error {
+		started = true
+		c.Header("Content-Type", spec.contentType)
+		c.Header("Content-Disposition", `attachment; filename="products.`+spec.extension+`"`)
+		c.Header("Vary", "Accept-Encoding")
+		c.Header("Trailer", exportStatusTrailer)
+
+		var out io.Writer = c.Writer
+		if gzipped {
+			c.Header("Content-Encoding", "gzip")
+			gz = gzip.NewWriter(c.Writer)
+			out = gz
+		}
+
+		// Buffer small writes; the buffer and the export cursor batch keep memory constant
+		buffered = bufio.NewWriterSize(out, 32*1024)
+		switch format {
+		case "csv":
+			writer = &csvExportWriter{w: csv.NewWriter(buffered)}
+		case "ndjson":
+			writer = &ndjsonExportWriter{enc: json.NewEncoder(buffered)}
+		case "json":
+			writer = &jsonExportWriter{w: buffered, enc: json.NewEncoder(buffered)}
+		}
+
+		c.Status(http.StatusOK)
+		return writer.begin()
+	}
+	// finish flushes what is buffered and ends the gzip stream
+	finish := func() error {
+		err := buffered.Flush()
+		if gz != nil {
+			if closeErr := gz.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		return err
+	}
+
+	start := time.Now()
+	exported, err := h.productService.ExportProducts(ctx, filter, func(product models.Product) error {
+		if !started {
+			if err := begin(); err != nil {
+				return err
+			}
+		}
+		return writer.write(product.ToResponse())
+	})
+	if err == nil && !started {
+		// No product matched, the file only has its header
+		err = begin()
+	}
+	if err == nil {
+		err = writer.end()
+	}
+	if err == nil {
+		err = finish()
+	}
+
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
+			"component": "handler",
+			"action":    "export_products",
+			"format":    format,
+			"exported":  exported,
+			"started":   started,
+		}).Error("Product export failed")
+
+		if !started {
+			abortWithError(c, err)
+			return
+		}
+
+		// The status is already sent: mark the span failed and end the file so it cannot pass for complete
+		span := trace.SpanFromContext(ctx)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "export failed after the response started")
+		_ = writer.fail(exported)
+		_ = finish()
+		c.Writer.Header().Set(exportStatusTrailer, "failed")
+		return
+	}
+	c.Writer.Header().Set(exportStatusTrailer, "complete")
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"component":   "handler",
+		"action":      "export_products",
+		"format":      format,
+		"gzip":        gzipped,
+		"exported":    exported,
+		"duration_ms": time.Since(start).Milliseconds(),
+	}).Info("Exported products")
+}
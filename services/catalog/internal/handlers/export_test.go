@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"catalog-service/internal/models"
+)
+
+func TestExportWriterFail(t *testing.T) {
+	product := models.ProductResponse{ID: 1, Name: "Widget", Price: 9.5, StockQty: 3}
+
+	tests := []struct {
+		format string
+		new    func(w io.Writer) exportWriter
+		// check fails the test if the broken-off file could pass for a complete one
+		check func(t *testing.T, body string)
+	}{
+		{
+			format: "csv",
+			new:    func(w io.Writer) exportWriter { return &csvExportWriter{w: csv.NewWriter(w)} },
+			check: func(t *testing.T, body string) {
+				if _, err := csv.NewReader(strings.NewReader(body)).ReadAll(); err == nil {
+					t.Errorf("CSV reader accepted the failed export:\n%s", body)
+				}
+				if !strings.HasSuffix(body, "# export failed after 1 products\n") {
+					t.Errorf("CSV export does not end with the error marker:\n%s", body)
+				}
+			},
+		},
+		{
+			format: "ndjson",
+			new:    func(w io.Writer) exportWriter { return &ndjsonExportWriter{enc: json.NewEncoder(w)} },
+			check: func(t *testing.T, body string) {
+				lines := strings.Split(strings.TrimSpace(body), "\n")
+				var marker struct {
+					Error    string `json:"error"`
+					Exported int    `json:"exported"`
+				}
+				if err := json.Unmarshal([]byte(lines[len(lines)-1]), &marker); err != nil || marker.Error == "" || marker.Exported != 1 {
+					t.Errorf("last NDJSON line %q is not the error marker", lines[len(lines)-1])
+				}
+			},
+		},
+		{
+			format: "json",
+			new: func(w io.Writer) exportWriter {
+				return &jsonExportWriter{w: w, enc: json.NewEncoder(w)}
+			},
+			check: func(t *testing.T, body string) {
+				var products []models.ProductResponse
+				if err := json.Unmarshal([]byte(body), &products); err == nil {
+					t.Errorf("the failed JSON export parses: %s", body)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			writer := tt.new(&buf)
+			if err := writer.begin(); err != nil {
+				t.Fatal(err)
+			}
+			if err := writer.write(product); err != nil {
+				t.Fatal(err)
+			}
+			if err := writer.fail(1); err != nil {
+				t.Fatalf("fail() error = %v", err)
+			}
+			tt.check(t, buf.String())
+		})
+	}
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{header: "", want: false},
+		{header: "gzip", want: true},
+		{header: "deflate, GZIP;q=0.8, br", want: true},
+		{header: "gzip;q=0", want: false},
+		{header: "gzip; q = 0", want: false},
+		{header: "identity", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := acceptsGzip(tt.header); got != tt.want {
+				t.Errorf("acceptsGzip(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"time"
 
+	"catalog-service/internal/logger"
+
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -157,8 +159,11 @@ func getUserAgentType(userAgent string) string {
 func (h *FrontendMetricsHandler) HandleFrontendMetrics(c *gin.Context) {
 	var payload FrontendMetricsPayload
 	if err := c.ShouldBindJSON(&payload); err != nil {
-		logrus.WithError(err).Error("Failed to decode frontend metrics payload")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON payload"})
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component": "handler",
+			"action":    "frontend_metrics",
+		}).Error("Failed to decode frontend metrics payload")
+		abortWithError(c, bindingError(err))
 		return
 	}
 
@@ -221,7 +226,9 @@ func (h *FrontendMetricsHandler) HandleFrontendMetrics(c *gin.Context) {
 	}
 
 	// Log the metrics reception
-	logrus.WithFields(logrus.Fields{
+	logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
+		"component":           "handler",
+		"action":              "frontend_metrics",
 		"session_id":          payload.SessionID,
 		"performance_metrics": len(payload.PerformanceMetrics),
 		"business_events":     len(payload.BusinessEvents),
@@ -231,12 +238,11 @@ func (h *FrontendMetricsHandler) HandleFrontendMetrics(c *gin.Context) {
 
 	// Return success
 	c.JSON(http.StatusOK, gin.H{
-		"status":  "success",
-		"message": "Frontend metrics processed successfully",
-		"metrics": gin.H{
+		"data": gin.H{
 			"performance": len(payload.PerformanceMetrics),
 			"business":    len(payload.BusinessEvents),
 			"errors":      len(payload.ErrorEvents),
 		},
+		"message": "Frontend metrics processed successfully",
 	})
 }
@@ -1,63 +1,75 @@
 package handlers
 
 import (
-	"database/sql"
 	"net/http"
 
-	"catalog-service/internal/logger"
+	"catalog-service/internal/health"
 
 	"github.com/gin-gonic/gin"
-	"github.com/sirupsen/logrus"
 )
 
-// HealthHandler handles health check requests
+// HealthHandler handles the probe endpoints
 type HealthHandler struct {
-	db *sql.DB
+	registry *health.Registry
 }
 
 // NewHealthHandler creates a new health handler
-func NewHealthHandler(db *sql.DB) *HealthHandler {
+func NewHealthHandler(registry *health.Registry) *HealthHandler {
 	return &HealthHandler{
-		db: db,
+		registry: registry,
 	}
 }
 
-// HealthCheck handles GET /health
+// Livez handles GET /livez
+func (h *HealthHandler) Livez(c *gin.Context) {
+	h.probe(c, health.Liveness)
+}
+
+// Readyz handles GET /readyz
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	h.probe(c, health.Readiness)
+}
+
+// Startupz handles GET /startupz
+func (h *HealthHandler) Startupz(c *gin.Context) {
+	h.probe(c, health.Startup)
+}
+
+// HealthCheck handles GET /health, kept for existing clients; it reports readiness
 func (h *HealthHandler) HealthCheck(c *gin.Context) {
-	// Check database connection
-	if h.db != nil {
-		err := h.db.Ping()
-		if err != nil {
-			logger.WithError(err).WithFields(logrus.Fields{
-				"component": "health",
-				"action":    "check",
-				"database":  "disconnected",
-			}).Error("Database health check failed")
-
-			c.JSON(http.StatusServiceUnavailable, gin.H{
-				"data": gin.H{
-					"status":   "unhealthy",
-					"database": "disconnected",
-					"service":  "catalog-service",
-				},
-				"error": err.Error(),
-			})
-			return
+	h.probe(c, health.Readiness)
+}
+
+// probe runs the checks of a probe and responds 200 or 503.
+// With ?verbose the response lists every check with its status and latency.
+func (h *HealthHandler) probe(c *gin.Context, probe health.Probe) {
+	healthy, results := h.registry.Run(c.Request.Context(), probe)
+
+	status := http.StatusOK
+	data := gin.H{
+		"status":  health.StatusOK,
+		"probe":   string(probe),
+		"service": "catalog-service",
+	}
+	if !healthy {
+		status = http.StatusServiceUnavailable
+		data["status"] = health.StatusFailing
+	}
+
+	if _, verbose := c.GetQuery("verbose"); verbose {
+		data["checks"] = results
+	} else if !healthy {
+		// Name the failing checks so a bare probe response is still useful
+		var failing []string
+		for _, result := range results {
+			if result.Critical && result.Status != health.StatusOK {
+				failing = append(failing, result.Name)
+			}
 		}
+		data["failing"] = failing
 	}
 
-	logger.WithFields(logrus.Fields{
-		"component": "health",
-		"action":    "check",
-		"status":    "healthy",
-		"database":  "connected",
-	}).Info("Health check successful")
-
-	c.JSON(http.StatusOK, gin.H{
-		"data": gin.H{
-			"status":   "healthy",
-			"database": "connected",
-			"service":  "catalog-service",
-		},
+	c.JSON(status, gin.H{
+		"data": data,
 	})
 }
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"catalog-service/internal/logger"
+	"catalog-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// maxImportLineBytes is the longest NDJSON line an import accepts
+const maxImportLineBytes = 1 << 20
+
+// importFileError marks an error that makes the rest of the import file unreadable
+type importFileError struct {
+	err error
+}
+
+func (e *importFileError) Error() string {
+	return e.err.Error()
+}
+
+// csvRowSource reads import rows from CSV with a header line.
+// Columns are matched by name, so their order does not matter.
+type csvRowSource struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+// csvImportColumns are the columns a CSV import may contain
+var csvImportColumns = []string{"external_key", "name", "description", "price", "stock_quantity"}
+
+func newCSVRowSource(r io.Reader) (*csvRowSource, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %v", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if i == 0 {
+			// Tolerate a UTF-8 byte order mark from spreadsheet exports
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		known := false
+		for _, column := range csvImportColumns {
+			known = known || column == name
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown CSV column %q (use %s)", name, strings.Join(csvImportColumns, ", "))
+		}
+		columns[name] = i
+	}
+	for _, required := range []string{"name", "price"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV header is missing the %q column", required)
+		}
+	}
+
+	return &csvRowSource{reader: reader, columns: columns}, nil
+}
+
+// field returns the value of a named column, or "" if the file has no such column
+func (s *csvRowSource) field(record []string, name string) string {
+	if i, ok := s.columns[name]; ok {
+		return strings.TrimSpace(record[i])
+	}
+	return ""
+}
+
+func (s *csvRowSource) Next() (models.ProductImportRow, error) {
+	record, err := s.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) && errors.Is(parseErr.Err, csv.ErrFieldCount) {
+			// A short or long row only affects that row
+			return models.ProductImportRow{}, &models.ImportRowError{Line: parseErr.StartLine, Message: "wrong number of fields"}
+		}
+		if err == io.EOF {
+			return models.ProductImportRow{}, err
+		}
+		return models.ProductImportRow{}, &importFileError{err}
+	}
+
+	line, _ := s.reader.FieldPos(0)
+	row := models.ProductImportRow{Line: line, ExternalKey: s.field(record, "external_key")}
+	row.Name = s.field(record, "name")
+	row.Description = s.field(record, "description")
+
+	if row.Price, err = strconv.ParseFloat(s.field(record, "price"), 64); err != nil {
+		return row, &models.ImportRowError{Line: line, ExternalKey: row.ExternalKey, Message: "price must be a number"}
+	}
+	if stock := s.field(record, "stock_quantity"); stock != "" {
+		if row.StockQty, err = strconv.Atoi(stock); err != nil {
+			return row, &models.ImportRowError{Line: line, ExternalKey: row.ExternalKey, Message: "stock_quantity must be an integer"}
+		}
+	}
+
+	return row, nil
+}
+
+// ndjsonRowSource reads import rows from newline-delimited JSON, one product object per line
+type ndjsonRowSource struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newNDJSONRowSource(r io.Reader) *ndjsonRowSource {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLineBytes)
+	return &ndjsonRowSource{scanner: scanner}
+}
+
+func (s *ndjsonRowSource) Next() (models.ProductImportRow, error) {
+	for s.scanner.Scan() {
+		s.line++
+		data := bytes.TrimSpace(s.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		row := models.ProductImportRow{Line: s.line}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&row); err != nil {
+			return row, &models.ImportRowError{Line: s.line, Message: "invalid JSON: " + err.Error()}
+		}
+		row.Line = s.line
+
+		return row, nil
+	}
+
+	if err := s.scanner.Err(); err != nil {
+		return models.ProductImportRow{}, &importFileError{fmt.Errorf("failed to read line %d: %v", s.line+1, err)}
+	}
+	return models.ProductImportRow{}, io.EOF
+}
+
+// importFormat picks the import format from ?format= or the Content-Type header
+func importFormat(c *gin.Context) string {
+	if format := c.Query("format"); format != "" {
+		return strings.ToLower(format)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	switch mediaType {
+	case "text/csv":
+		return "csv"
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return "ndjson"
+	}
+	return ""
+}
+
+// ImportProducts handles POST /api/v1/products/import
+// The body is CSV or NDJSON and is read as a stream. mode=all_or_nothing (default) imports
+// nothing if any row is rejected; mode=best_effort imports every valid row.
+func (h *ProductHandler) ImportProducts(c *gin.Context) {
+	mode := c.DefaultQuery("mode", models.ImportAllOrNothing)
+	if mode != models.ImportAllOrNothing && mode != models.ImportBestEffort {
+		abortWithError(c, models.NewValidationError("mode", "invalid_choice", "must be one of all_or_nothing, best_effort"))
+		return
+	}
+
+	var source models.ImportRowSource
+	format := importFormat(c)
+	switch format {
+	case "csv":
+		csvSource, err := newCSVRowSource(c.Request.Body)
+		if err != nil {
+			abortWithError(c, newStatusError(http.StatusBadRequest, "invalid_import_file", err.Error()))
+			return
+		}
+		source = csvSource
+	case "ndjson":
+		source = newNDJSONRowSource(c.Request.Body)
+	default:
+		abortWithError(c, newStatusError(http.StatusUnsupportedMediaType, "unsupported_media_type",
+			"Import body must be text/csv or application/x-ndjson (or pass format=csv|ndjson)"))
+		return
+	}
+
+	report, err := h.productService.ImportProducts(c.Request.Context(), source, mode)
+	if err != nil {
+		var fileErr *importFileError
+		if errors.As(err, &fileErr) {
+			logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+				"component": "handler",
+				"action":    "import_products",
+				"format":    format,
+			}).Warn("Malformed import file")
+
+			abortWithError(c, newStatusError(http.StatusBadRequest, "invalid_import_file", err.Error()))
+			return
+		}
+
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component": "handler",
+			"action":    "import_products",
+			"format":    format,
+			"mode":      mode,
+		}).Error("Failed to import products")
+
+		abortWithError(c, err)
+		return
+	}
+
+	logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
+		"component": "handler",
+		"action":    "import_products",
+		"format":    format,
+		"mode":      mode,
+		"rows":      report.Rows,
+		"rejected":  len(report.Rejected),
+		"committed": report.Committed,
+	}).Info("Product import finished")
+
+	if !report.Committed {
+		importErr := newStatusError(http.StatusUnprocessableEntity, "import_rejected", "Import rejected, nothing was imported")
+		importErr.details = report
+		abortWithError(c, importErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"catalog-service/internal/logger"
+	"catalog-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// InventoryHandler handles stock reservation requests
+type InventoryHandler struct {
+	reservationService *models.ReservationService
+}
+
+// NewInventoryHandler creates a new inventory handler
+func NewInventoryHandler(reservationService *models.ReservationService) *InventoryHandler {
+	return &InventoryHandler{
+		reservationService: reservationService,
+	}
+}
+
+// rejectedReservation reports whether a reservation error is an expected rejection, such as
+// insufficient stock or an expired reservation, rather than a failure of the service
+func rejectedReservation(err error) bool {
+	return errors.Is(err, models.ErrNotFound) || errors.Is(err, models.ErrConflict)
+}
+
+// parseReservationID parses the :id path parameter, writing a 400 response if it is invalid
+func parseReservationID(c *gin.Context, action string) (string, bool) {
+	idStr := c.Param("id")
+	if _, err := uuid.Parse(idStr); err != nil {
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component": "handler",
+			"action":    action,
+			"id_param":  idStr,
+		}).Error("Invalid reservation ID")
+
+		abortWithError(c, models.NewValidationError("id", models.CodeInvalid, "must be a reservation UUID"))
+		return "", false
+	}
+	return idStr, true
+}
+
+// CreateReservation handles POST /api/v1/inventory/reservations
+func (h *InventoryHandler) CreateReservation(c *gin.Context) {
+	var req models.ReservationCreateRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component": "handler",
+			"action":    "create_reservation",
+		}).Error("Invalid request data")
+
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	reservation, err := h.reservationService.CreateReservation(c.Request.Context(), req)
+	if err != nil {
+		if rejectedReservation(err) {
+			logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
+				"component": "handler",
+				"action":    "create_reservation",
+				"reason":    err.Error(),
+			}).Warn("Reservation rejected")
+
+			abortWithError(c, err)
+			return
+		}
+
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component": "handler",
+			"action":    "create_reservation",
+		}).Error("Failed to create reservation")
+
+		abortWithError(c, err)
+		return
+	}
+
+	logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
+		"component":      "handler",
+		"action":         "create_reservation",
+		"reservation_id": reservation.ID,
+	}).Info("Reservation created successfully")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": reservation,
+	})
+}
+
+// GetReservation handles GET /api/v1/inventory/reservations/:id
+func (h *InventoryHandler) GetReservation(c *gin.Context) {
+	id, ok := parseReservationID(c, "get_reservation")
+	if !ok {
+		return
+	}
+
+	reservation, err := h.reservationService.GetReservation(c.Request.Context(), id)
+	if err != nil {
+		if rejectedReservation(err) {
+			abortWithError(c, err)
+			return
+		}
+
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component":      "handler",
+			"action":         "get_reservation",
+			"reservation_id": id,
+		}).Error("Failed to retrieve reservation")
+
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": reservation,
+	})
+}
+
+// CommitReservation handles POST /api/v1/inventory/reservations/:id/commit
+func (h *InventoryHandler) CommitReservation(c *gin.Context) {
+	h.finishReservation(c, "commit_reservation", h.reservationService.CommitReservation)
+}
+
+// ReleaseReservation handles DELETE /api/v1/inventory/reservations/:id
+func (h *InventoryHandler) ReleaseReservation(c *gin.Context) {
+	h.finishReservation(c, "release_reservation", h.reservationService.ReleaseReservation)
+}
+
+// finishReservation runs a commit or release and writes the response
+func (h *InventoryHandler) finishReservation(c *gin.Context, action string, finish func(context.Context, string) (*models.Reservation, error)) {
+	id, ok := parseReservationID(c, action)
+	if !ok {
+		return
+	}
+
+	reservation, err := finish(c.Request.Context(), id)
+	if err != nil {
+		if rejectedReservation(err) {
+			logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
+				"component":      "handler",
+				"action":         action,
+				"reservation_id": id,
+				"reason":         err.Error(),
+			}).Warn("Reservation change rejected")
+
+			abortWithError(c, err)
+			return
+		}
+
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component":      "handler",
+			"action":         action,
+			"reservation_id": id,
+		}).Error("Failed to update reservation")
+
+		abortWithError(c, err)
+		return
+	}
+
+	logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
+		"component":      "handler",
+		"action":         action,
+		"reservation_id": id,
+		"status":         reservation.Status,
+	}).Info("Reservation updated successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": reservation,
+	})
+}
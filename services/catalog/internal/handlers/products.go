@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"catalog-service/internal/logger"
 	"catalog-service/internal/models"
@@ -26,12 +30,73 @@ func NewProductHandler(productService *models.ProductService, analysisService *s
 	}
 }
 
+// parseProductFilter reads the listing filters and sort order from the query string:
+// category, min_price, max_price, in_stock, name_contains, sort and order
+func parseProductFilter(c *gin.Context) (models.ProductFilter, error) {
+	var filter models.ProductFilter
+
+	if categoryStr := c.Query("category"); categoryStr != "" {
+		categoryID, err := strconv.Atoi(categoryStr)
+		if err != nil {
+			return filter, models.NewValidationError("category", models.CodeInvalid, "must be a category ID")
+		}
+		filter.CategoryID = &categoryID
+	}
+
+	parsePrice := func(name string) (*float64, error) {
+		value := c.Query(name)
+		if value == "" {
+			return nil, nil
+		}
+		price, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, models.NewValidationError(name, models.CodeInvalid, "must be a non-negative number")
+		}
+		return &price, nil
+	}
+
+	var err error
+	if filter.MinPrice, err = parsePrice("min_price"); err != nil {
+		return filter, err
+	}
+	if filter.MaxPrice, err = parsePrice("max_price"); err != nil {
+		return filter, err
+	}
+
+	if inStockStr := c.Query("in_stock"); inStockStr != "" {
+		inStock, err := strconv.ParseBool(inStockStr)
+		if err != nil {
+			return filter, models.NewValidationError("in_stock", models.CodeInvalid, "must be true or false")
+		}
+		filter.InStock = &inStock
+	}
+
+	filter.NameContains = c.Query("name_contains")
+
+	if filter.Sort, err = models.ParseProductSort(c.Query("sort"), c.Query("order")); err != nil {
+		return filter, err
+	}
+
+	// Price range and name search rules are shared with the gRPC API
+	return filter, filter.Validate()
+}
+
 // GetProducts handles GET /api/v1/products
+// Supports two pagination modes:
+//   - page/limit (offset based, the original mode)
+//   - cursor/limit (keyset based); pass an empty cursor for the first page, then next_cursor
+//
+// include_total=true adds the total number of matching products.
+// ids=1,2,3 switches to a batch lookup, like POST /api/v1/products/batch-get.
 func (h *ProductHandler) GetProducts(c *gin.Context) {
-	// Parse query parameters for pagination
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil || page < 1 {
-		page = 1
+	if idsStr, ok := c.GetQuery("ids"); ok {
+		ids, err := parseProductIDs(idsStr)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		h.batchGetProducts(c, ids)
+		return
 	}
 
 	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
@@ -39,92 +104,320 @@ func (h *ProductHandler) GetProducts(c *gin.Context) {
 		limit = 50
 	}
 
-	offset := (page - 1) * limit
+	// Parse optional filters and sort order
+	filter, err := parseProductFilter(c)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component": "handler",
+			"action":    "get_products",
+			"query":     c.Request.URL.RawQuery,
+		}).Warn("Invalid product filter")
+
+		abortWithError(c, err)
+		return
+	}
+
+	// Choose the pagination mode
+	cursorStr, cursorMode := c.GetQuery("cursor")
+	page := 1
+	pageReq := models.ProductPage{Limit: limit}
+
+	if cursorMode {
+		// Fetch one extra row to find out whether there is a next page
+		pageReq.Limit = limit + 1
+
+		if cursorStr != "" {
+			cursor, err := models.DecodeProductCursor(cursorStr)
+			if err == nil {
+				err = applyCursorSort(c, cursor, &filter)
+			}
+			if err != nil {
+				abortWithError(c, err)
+				return
+			}
+			pageReq.After = cursor
+		}
+	} else {
+		page, err = strconv.Atoi(c.DefaultQuery("page", "1"))
+		if err != nil || page < 1 {
+			page = 1
+		}
+		pageReq.Offset = (page - 1) * limit
+	}
 
 	// Get products from database
-	products, err := h.productService.GetAllProducts(c, offset, limit)
+	products, err := h.productService.GetAllProducts(c.Request.Context(), filter, pageReq)
 	if err != nil {
-		logger.WithError(err).WithFields(logrus.Fields{
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
 			"component": "handler",
 			"action":    "get_products",
 			"page":      page,
 			"limit":     limit,
 		}).Error("Failed to retrieve products")
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve products",
-		})
+		abortWithError(c, err)
 		return
 	}
 
+	response := gin.H{
+		"limit": limit,
+		"sort":  filter.Sort.String(),
+	}
+
+	if cursorMode {
+		hasMore := len(products) > limit
+		var nextCursor *string
+		if hasMore {
+			products = products[:limit]
+			encoded := models.NewProductCursor(products[limit-1], filter.Sort).Encode()
+			nextCursor = &encoded
+		}
+		response["next_cursor"] = nextCursor
+		response["has_more"] = hasMore
+	} else {
+		response["page"] = page
+	}
+
+	if includeTotal, _ := strconv.ParseBool(c.Query("include_total")); includeTotal {
+		total, err := h.productService.GetProductCount(c.Request.Context(), filter)
+		if err != nil {
+			logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+				"component": "handler",
+				"action":    "get_products",
+			}).Error("Failed to count products")
+
+			abortWithError(c, err)
+			return
+		}
+		response["total"] = total
+	}
+
 	// Convert to response format
-	var responses []models.ProductResponse
+	responses := make([]models.ProductResponse, 0, len(products))
 	for _, product := range products {
 		responses = append(responses, product.ToResponse())
 	}
+	response["data"] = responses
+	response["count"] = len(responses)
+
+	logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
+		"component":   "handler",
+		"action":      "get_products",
+		"page":        page,
+		"limit":       limit,
+		"count":       len(responses),
+		"cursor_mode": cursorMode,
+	}).Info("Retrieved products")
+
+	c.JSON(http.StatusOK, response)
+}
+
+// applyCursorSort makes sure the cursor is used with the sort order it was created for.
+// If no sort is given in the request, the cursor's sort is used.
+func applyCursorSort(c *gin.Context, cursor *models.ProductCursor, filter *models.ProductFilter) error {
+	cursorSort, err := cursor.ProductSort()
+	if err != nil {
+		return err
+	}
+
+	if c.Query("sort") == "" && c.Query("order") == "" {
+		filter.Sort = cursorSort
+		return nil
+	}
+	if cursorSort != filter.Sort {
+		return models.NewValidationError("cursor", "sort_mismatch", fmt.Sprintf("was created for sort %s, not %s", cursorSort, filter.Sort))
+	}
+	return nil
+}
+
+// SearchProducts handles GET /api/v1/products/search?q=mac
+// Every word in q is matched as a prefix, so partial input works for type-ahead.
+func (h *ProductHandler) SearchProducts(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		abortWithError(c, models.NewValidationError("q", models.CodeRequired, "is required"))
+		return
+	}
+
+	// Parse query parameters for pagination
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
 
-	logger.WithFields(logrus.Fields{
+	offset := (page - 1) * limit
+
+	results, err := h.productService.SearchProducts(c.Request.Context(), query, offset, limit)
+	if err != nil {
+		if errors.Is(err, models.ErrNoSearchTerms) {
+			abortWithError(c, models.NewValidationError("q", models.CodeInvalid, err.Error()))
+			return
+		}
+
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component": "handler",
+			"action":    "search_products",
+			"query":     query,
+		}).Error("Failed to search products")
+
+		abortWithError(c, err)
+		return
+	}
+
+	responses := make([]models.ProductSearchResponse, 0, len(results))
+	for _, result := range results {
+		responses = append(responses, result.ToResponse())
+	}
+
+	logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
 		"component": "handler",
-		"action":    "get_products",
-		"page":      page,
-		"limit":     limit,
+		"action":    "search_products",
+		"query":     query,
 		"count":     len(responses),
-	}).Info("Retrieved products")
+	}).Info("Searched products")
 
 	c.JSON(http.StatusOK, gin.H{
 		"data":  responses,
+		"query": query,
 		"page":  page,
 		"limit": limit,
 		"count": len(responses),
 	})
 }
 
+// parseProductIDs parses a comma-separated list of product IDs
+func parseProductIDs(value string) ([]int, error) {
+	var ids []int
+	for _, part := range strings.Split(value, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || id < 1 {
+			return nil, models.NewValidationError("ids", models.CodeInvalid, "must be a comma-separated list of product IDs")
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) > models.MaxBatchGetProducts {
+		return nil, models.NewValidationError("ids", models.CodeOutOfRange, fmt.Sprintf("at most %d ids per request", models.MaxBatchGetProducts))
+	}
+	return ids, nil
+}
+
+// BatchGetProducts handles POST /api/v1/products/batch-get
+func (h *ProductHandler) BatchGetProducts(c *gin.Context) {
+	var req models.ProductBatchGetRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component": "handler",
+			"action":    "batch_get_products",
+		}).Error("Invalid request data")
+
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	h.batchGetProducts(c, req.IDs)
+}
+
+// batchGetProducts looks up several products in one query and writes them in request order.
+// Missing IDs are not an error; they are listed under not_found.
+func (h *ProductHandler) batchGetProducts(c *gin.Context, ids []int) {
+	products, notFound, err := h.productService.GetProductsByIDs(c.Request.Context(), ids)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component": "handler",
+			"action":    "batch_get_products",
+			"requested": len(ids),
+		}).Error("Failed to retrieve products")
+
+		abortWithError(c, err)
+		return
+	}
+
+	responses := make([]models.ProductResponse, 0, len(products))
+	for _, product := range products {
+		responses = append(responses, product.ToResponse())
+	}
+
+	logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
+		"component": "handler",
+		"action":    "batch_get_products",
+		"requested": len(ids),
+		"count":     len(responses),
+		"not_found": len(notFound),
+	}).Info("Retrieved products by ID")
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":      responses,
+		"not_found": notFound,
+		"count":     len(responses),
+	})
+}
+
 // GetProduct handles GET /api/v1/products/:id
 func (h *ProductHandler) GetProduct(c *gin.Context) {
 	// Parse product ID
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		logger.WithError(err).WithFields(logrus.Fields{
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
 			"component": "handler",
 			"action":    "get_product",
 			"id_param":  idStr,
 		}).Error("Invalid product ID")
 
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid product ID",
-		})
+		abortWithError(c, invalidIDError("id"))
 		return
 	}
 
 	// Get product from database
-	product, err := h.productService.GetProduct(c, id)
+	product, err := h.productService.GetProduct(c.Request.Context(), id)
 	if err != nil {
-		if err.Error() == "product not found" {
-			logger.WithFields(logrus.Fields{
+		if errors.Is(err, models.ErrProductNotFound) {
+			logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
 				"component":  "handler",
 				"action":     "get_product",
 				"product_id": id,
 			}).Warn("Product not found")
 
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Product not found",
-			})
+			abortWithError(c, err)
 			return
 		}
 
-		logger.WithError(err).WithFields(logrus.Fields{
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
 			"component":  "handler",
 			"action":     "get_product",
 			"product_id": id,
 		}).Error("Failed to retrieve product")
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve product",
-		})
+		abortWithError(c, err)
 		return
 	}
 
+	// Conditional GET: HTTP dates only have second precision, so compare truncated times
+	etag := productETag(product.Version)
+	lastModified := product.UpdatedAt.UTC().Truncate(time.Second)
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+
+	// If-None-Match takes precedence over If-Modified-Since when both are sent
+	if inm := c.GetHeader("If-None-Match"); inm != "" {
+		if etagMatches(inm, etag) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	} else if ims := c.GetHeader("If-Modified-Since"); ims != "" {
+		if since, err := http.ParseTime(ims); err == nil && !lastModified.After(since) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"data": product.ToResponse(),
 	})
@@ -136,35 +429,40 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 
 	// Bind and validate request
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.WithError(err).WithFields(logrus.Fields{
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
 			"component": "handler",
 			"action":    "create_product",
 		}).Error("Invalid request data")
 
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		abortWithError(c, bindingError(err))
 		return
 	}
 
 	// Create product in database
-	product, err := h.productService.CreateProduct(c, req)
+	product, err := h.productService.CreateProduct(c.Request.Context(), req)
 	if err != nil {
-		logger.WithError(err).WithFields(logrus.Fields{
+		if errors.Is(err, models.ErrValidation) {
+			logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+				"component": "handler",
+				"action":    "create_product",
+			}).Warn("Invalid product")
+
+			abortWithError(c, err)
+			return
+		}
+
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
 			"component": "handler",
 			"action":    "create_product",
 			"name":      req.Name,
 			"price":     req.Price,
 		}).Error("Failed to create product")
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to create product",
-		})
+		abortWithError(c, err)
 		return
 	}
 
-	logger.WithFields(logrus.Fields{
+	logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
 		"component":  "handler",
 		"action":     "create_product",
 		"product_id": product.ID,
@@ -183,15 +481,13 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		logger.WithError(err).WithFields(logrus.Fields{
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
 			"component": "handler",
 			"action":    "update_product",
 			"id_param":  idStr,
 		}).Error("Invalid product ID")
 
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid product ID",
-		})
+		abortWithError(c, invalidIDError("id"))
 		return
 	}
 
@@ -199,56 +495,96 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 
 	// Bind and validate request
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.WithError(err).WithFields(logrus.Fields{
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
 			"component":  "handler",
 			"action":     "update_product",
 			"product_id": id,
 		}).Error("Invalid request data")
 
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	// Optional optimistic concurrency check: If-Match carries the ETag the client last saw
+	ifMatch, err := parseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		abortWithError(c, models.NewValidationError("If-Match", models.CodeInvalid, err.Error()))
 		return
 	}
 
 	// Update product in database
-	product, err := h.productService.UpdateProduct(c, id, req)
+	product, err := h.productService.UpdateProduct(c.Request.Context(), id, req, ifMatch)
 	if err != nil {
-		if err.Error() == "product not found" {
-			logger.WithFields(logrus.Fields{
+		if errors.Is(err, models.ErrValidation) {
+			logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+				"component":  "handler",
+				"action":     "update_product",
+				"product_id": id,
+			}).Warn("Invalid product update")
+
+			abortWithError(c, err)
+			return
+		}
+
+		if errors.Is(err, models.ErrVersionMismatch) {
+			logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
+				"component":       "handler",
+				"action":          "update_product",
+				"product_id":      id,
+				"if_match":        c.GetHeader("If-Match"),
+				"current_version": product.Version,
+			}).Warn("Product was modified by another request")
+
+			c.Header("ETag", productETag(product.Version))
+			abortWithError(c, err)
+			return
+		}
+
+		if errors.Is(err, models.ErrConflict) {
+			logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+				"component":  "handler",
+				"action":     "update_product",
+				"product_id": id,
+			}).Warn("Stock update conflicts with reservations")
+
+			abortWithError(c, err)
+			return
+		}
+
+		if errors.Is(err, models.ErrProductNotFound) {
+			logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
 				"component":  "handler",
 				"action":     "update_product",
 				"product_id": id,
 			}).Warn("Product not found")
 
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Product not found",
-			})
+			abortWithError(c, err)
 			return
 		}
 
-		logger.WithError(err).WithFields(logrus.Fields{
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
 			"component":  "handler",
 			"action":     "update_product",
 			"product_id": id,
 		}).Error("Failed to update product")
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to update product",
-		})
+		abortWithError(c, err)
 		return
 	}
 
-	logger.WithFields(logrus.Fields{
+	logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
 		"component":  "handler",
 		"action":     "update_product",
 		"product_id": product.ID,
 		"name":       product.Name,
 		"price":      product.Price,
+		"version":    product.Version,
 	}).Info("Product updated successfully")
 
-	c.JSON(http.StatusOK, product.ToResponse())
+	c.Header("ETag", productETag(product.Version))
+	c.JSON(http.StatusOK, gin.H{
+		"data": product.ToResponse(),
+	})
 }
 
 // DeleteProduct handles DELETE /api/v1/products/:id
@@ -257,53 +593,48 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		logger.WithError(err).WithFields(logrus.Fields{
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
 			"component": "handler",
 			"action":    "delete_product",
 			"id_param":  idStr,
 		}).Error("Invalid product ID")
 
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid product ID",
-		})
+		abortWithError(c, invalidIDError("id"))
 		return
 	}
 
 	// Delete product from database
-	err = h.productService.DeleteProduct(c, id)
+	err = h.productService.DeleteProduct(c.Request.Context(), id)
 	if err != nil {
-		if err.Error() == "product not found" {
-			logger.WithFields(logrus.Fields{
+		if errors.Is(err, models.ErrProductNotFound) {
+			logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
 				"component":  "handler",
 				"action":     "delete_product",
 				"product_id": id,
 			}).Warn("Product not found")
 
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Product not found",
-			})
+			abortWithError(c, err)
 			return
 		}
 
-		logger.WithError(err).WithFields(logrus.Fields{
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
 			"component":  "handler",
 			"action":     "delete_product",
 			"product_id": id,
 		}).Error("Failed to delete product")
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to delete product",
-		})
+		abortWithError(c, err)
 		return
 	}
 
-	logger.WithFields(logrus.Fields{
+	logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
 		"component":  "handler",
 		"action":     "delete_product",
 		"product_id": id,
 	}).Info("Product deleted successfully")
 
 	c.JSON(http.StatusOK, gin.H{
+		"data":    gin.H{"id": id},
 		"message": "Product deleted successfully",
 	})
 }
@@ -316,15 +647,13 @@ func (h *ProductHandler) AnalyzeProduct(c *gin.Context) {
 	if idStr := c.Query("id"); idStr != "" {
 		id, err := strconv.Atoi(idStr)
 		if err != nil {
-			logger.WithError(err).WithFields(logrus.Fields{
+			logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
 				"component": "handler",
 				"action":    "analyze_product",
 				"id_param":  idStr,
 			}).Error("Invalid product ID")
 
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Invalid product ID",
-			})
+			abortWithError(c, invalidIDError("id"))
 			return
 		}
 		productID = &id
@@ -333,19 +662,17 @@ func (h *ProductHandler) AnalyzeProduct(c *gin.Context) {
 	// Perform complex analysis that creates multiple spans
 	result, err := h.analysisService.AnalyzeProduct(c, productID)
 	if err != nil {
-		logger.WithError(err).WithFields(logrus.Fields{
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
 			"component":  "handler",
 			"action":     "analyze_product",
 			"product_id": productID,
 		}).Error("Failed to analyze product")
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to analyze product",
-		})
+		abortWithError(c, err)
 		return
 	}
 
-	logger.WithFields(logrus.Fields{
+	logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
 		"component":  "handler",
 		"action":     "analyze_product",
 		"product_id": productID,
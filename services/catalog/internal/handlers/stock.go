@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"catalog-service/internal/logger"
+	"catalog-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// AdjustStock handles POST /api/v1/products/:id/stock/adjustments
+func (h *ProductHandler) AdjustStock(c *gin.Context) {
+	// Parse product ID
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component": "handler",
+			"action":    "adjust_stock",
+			"id_param":  idStr,
+		}).Error("Invalid product ID")
+
+		abortWithError(c, invalidIDError("id"))
+		return
+	}
+
+	var req models.StockAdjustmentRequest
+
+	// Bind and validate request; a zero delta fails the required check
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component":  "handler",
+			"action":     "adjust_stock",
+			"product_id": id,
+		}).Error("Invalid request data")
+
+		abortWithError(c, bindingError(err))
+		return
+	}
+
+	product, movement, err := h.productService.AdjustStock(c.Request.Context(), id, req)
+	if err != nil {
+		var stockErr *models.InsufficientStockError
+		if errors.As(err, &stockErr) {
+			logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
+				"component":  "handler",
+				"action":     "adjust_stock",
+				"product_id": id,
+				"delta":      req.Delta,
+				"available":  stockErr.Available,
+			}).Warn("Stock adjustment rejected")
+
+			abortWithError(c, err)
+			return
+		}
+
+		if errors.Is(err, models.ErrProductNotFound) {
+			abortWithError(c, err)
+			return
+		}
+
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component":  "handler",
+			"action":     "adjust_stock",
+			"product_id": id,
+		}).Error("Failed to adjust stock")
+
+		abortWithError(c, err)
+		return
+	}
+
+	logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
+		"component":   "handler",
+		"action":      "adjust_stock",
+		"product_id":  id,
+		"movement_id": movement.ID,
+	}).Info("Stock adjusted successfully")
+
+	c.Header("ETag", productETag(product.Version))
+	c.JSON(http.StatusCreated, gin.H{
+		"data": gin.H{
+			"product":  product.ToResponse(),
+			"movement": movement,
+		},
+	})
+}
+
+// GetStockMovements handles GET /api/v1/products/:id/stock/adjustments
+func (h *ProductHandler) GetStockMovements(c *gin.Context) {
+	// Parse product ID
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component": "handler",
+			"action":    "get_stock_movements",
+			"id_param":  idStr,
+		}).Error("Invalid product ID")
+
+		abortWithError(c, invalidIDError("id"))
+		return
+	}
+
+	// Parse query parameters for pagination
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	movements, err := h.productService.GetStockMovements(c.Request.Context(), id, (page-1)*limit, limit)
+	if err != nil {
+		if errors.Is(err, models.ErrProductNotFound) {
+			abortWithError(c, err)
+			return
+		}
+
+		logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+			"component":  "handler",
+			"action":     "get_stock_movements",
+			"product_id": id,
+		}).Error("Failed to retrieve stock movements")
+
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  movements,
+		"page":  page,
+		"limit": limit,
+		"count": len(movements),
+	})
+}
@@ -0,0 +1,58 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+
+	"catalog-service/internal/db"
+)
+
+// ShutdownCheck fails once shuttingDown is set, taking the pod out of rotation while it drains
+func ShutdownCheck(shuttingDown *atomic.Bool) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if shuttingDown.Load() {
+			return errors.New("shutting down")
+		}
+		return nil
+	}
+}
+
+// DatabaseCheck pings the database
+func DatabaseCheck(database *sql.DB) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if err := database.PingContext(ctx); err != nil {
+			return fmt.Errorf("database ping failed: %w", err)
+		}
+		return nil
+	}
+}
+
+// MigrationsCheck fails while any embedded migration has not been applied
+func MigrationsCheck(database *db.Database) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		pending, err := database.PendingMigrations(ctx)
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("%d migrations pending, first is %d", len(pending), pending[0])
+		}
+		return nil
+	}
+}
+
+// TCPCheck opens and closes a TCP connection to address, such as the OTLP exporter endpoint
+func TCPCheck(address string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return fmt.Errorf("%s unreachable: %w", address, err)
+		}
+		return conn.Close()
+	}
+}
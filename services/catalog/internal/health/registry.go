@@ -0,0 +1,164 @@
+// Package health runs the dependency checks behind the liveness, readiness and startup probes.
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"catalog-service/internal/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Probe identifies one of the Kubernetes probes
+type Probe string
+
+const (
+	// Liveness fails only when the process itself is broken and must be restarted
+	Liveness Probe = "livez"
+	// Readiness fails when the pod should not receive traffic
+	Readiness Probe = "readyz"
+	// Startup fails until the pod has finished starting
+	Startup Probe = "startupz"
+)
+
+// Check statuses
+const (
+	StatusOK      = "ok"
+	StatusFailing = "failing"
+)
+
+// Check is a single dependency check
+type Check struct {
+	Name string
+	// Run returns an error when the dependency is unhealthy
+	Run func(ctx context.Context) error
+	// Timeout bounds a single run of the check
+	Timeout time.Duration
+	// CacheTTL is how long a result is reused before the check runs again
+	CacheTTL time.Duration
+	// Critical checks fail the probe; other checks are only reported
+	Critical bool
+	// Probes lists the probes this check belongs to
+	Probes []Probe
+}
+
+// Result is the outcome of one check
+type Result struct {
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	Critical  bool      `json:"critical"`
+	LatencyMS float64   `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// registeredCheck holds a check and its last result
+type registeredCheck struct {
+	Check
+
+	mu   sync.Mutex
+	last *Result
+}
+
+// Registry holds the checks for every probe
+type Registry struct {
+	mu     sync.RWMutex
+	checks []*registeredCheck
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a check to the registry
+func (r *Registry) Register(check Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, &registeredCheck{Check: check})
+}
+
+// Run runs every check of the probe concurrently, reusing cached results that are still fresh.
+// The probe is healthy when no critical check is failing.
+func (r *Registry) Run(ctx context.Context, probe Probe) (bool, []Result) {
+	r.mu.RLock()
+	var checks []*registeredCheck
+	for _, check := range r.checks {
+		for _, p := range check.Probes {
+			if p == probe {
+				checks = append(checks, check)
+				break
+			}
+		}
+	}
+	r.mu.RUnlock()
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check *registeredCheck) {
+			defer wg.Done()
+			results[i] = check.result(ctx)
+		}(i, check)
+	}
+	wg.Wait()
+
+	healthy := true
+	for _, result := range results {
+		if result.Critical && result.Status != StatusOK {
+			healthy = false
+		}
+	}
+	return healthy, results
+}
+
+// result returns the cached result if it is fresh, otherwise runs the check.
+// Concurrent probes wait for a single run instead of each hitting the dependency.
+func (c *registeredCheck) result(ctx context.Context) Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.last != nil && time.Since(c.last.CheckedAt) < c.CacheTTL {
+		return *c.last
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.Run(checkCtx)
+	result := Result{
+		Name:      c.Name,
+		Status:    StatusOK,
+		Critical:  c.Critical,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+		CheckedAt: time.Now(),
+	}
+	if err != nil {
+		result.Status = StatusFailing
+		result.Error = err.Error()
+	}
+
+	// Log only when a check changes state, probes run far too often to log every result
+	if c.last == nil || c.last.Status != result.Status {
+		entry := logger.WithFields(logrus.Fields{
+			"component":  "health",
+			"action":     "check",
+			"check":      c.Name,
+			"status":     result.Status,
+			"critical":   c.Critical,
+			"latency_ms": result.LatencyMS,
+		})
+		if err != nil {
+			entry.WithError(err).Warn("Health check failing")
+		} else if c.last != nil {
+			entry.Info("Health check recovered")
+		}
+	}
+
+	c.last = &result
+	return result
+}
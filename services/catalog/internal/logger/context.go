@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// fieldsKey is the context key of the per-request log fields
+type fieldsKey struct{}
+
+// ContextWithFields returns a copy of ctx whose loggers include fields, in addition to the
+// fields added earlier. Middleware uses it for per-request fields such as the request id.
+func ContextWithFields(ctx context.Context, fields logrus.Fields) context.Context {
+	merged := logrus.Fields{}
+	for key, value := range contextFields(ctx) {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// FromContext returns a logger entry with the per-request fields of ctx and the trace_id and
+// span_id of its current span, so every log line of a request can be found from its trace
+func FromContext(ctx context.Context) *logrus.Entry {
+	entry := Logger.WithContext(ctx)
+
+	if fields := contextFields(ctx); len(fields) > 0 {
+		entry = entry.WithFields(fields)
+	}
+
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		entry = entry.WithFields(logrus.Fields{
+			"trace_id": spanCtx.TraceID().String(),
+			"span_id":  spanCtx.SpanID().String(),
+		})
+	}
+
+	return entry
+}
+
+func contextFields(ctx context.Context) logrus.Fields {
+	fields, _ := ctx.Value(fieldsKey{}).(logrus.Fields)
+	return fields
+}
@@ -22,20 +22,21 @@ func init() {
 		},
 	})
 
-	// Set log level from environment or default to info
-	level := os.Getenv("LOG_LEVEL")
-	if level == "" {
-		level = "info"
-	}
+	// Default to info until SetLevel applies the configured level
+	Logger.SetLevel(logrus.InfoLevel)
+
+	// Output to stdout for container environments
+	Logger.SetOutput(os.Stdout)
+}
 
+// SetLevel sets the minimum level that is logged, e.g. "debug" or "warn"
+func SetLevel(level string) error {
 	logLevel, err := logrus.ParseLevel(level)
 	if err != nil {
-		logLevel = logrus.InfoLevel
+		return err
 	}
 	Logger.SetLevel(logLevel)
-
-	// Output to stdout for container environments
-	Logger.SetOutput(os.Stdout)
+	return nil
 }
 
 // WithFields creates a new logger entry with structured fields
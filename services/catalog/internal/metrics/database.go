@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// DBQueryMetrics holds the per-query database metrics
+type DBQueryMetrics struct {
+	QueryDuration *prometheus.HistogramVec
+	QueryErrors   *prometheus.CounterVec
+}
+
+// NewDBQueryMetrics creates and registers database query metrics.
+// It must be called once per process; the models package holds the only instance.
+func NewDBQueryMetrics() *DBQueryMetrics {
+	return &DBQueryMetrics{
+		QueryDuration: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "catalog_db_query_duration_seconds",
+				Help:    "Duration of database queries made by the catalog service",
+				Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+			},
+			[]string{"operation", "table"},
+		),
+		QueryErrors: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "catalog_db_query_errors_total",
+				Help: "Total number of failed database queries made by the catalog service",
+			},
+			[]string{"operation", "table"},
+		),
+	}
+}
+
+// RecordQuery records metrics for a query. operation is the span name without the "db."
+// prefix, e.g. "get_product", and table the db.table span attribute.
+func (m *DBQueryMetrics) RecordQuery(operation, table string, duration float64, failed bool) {
+	m.QueryDuration.WithLabelValues(operation, table).Observe(duration)
+	if failed {
+		m.QueryErrors.WithLabelValues(operation, table).Inc()
+	}
+}
+
+// dbStatsCollector exports sql.DBStats of a connection pool, read at scrape time
+type dbStatsCollector struct {
+	db *sql.DB
+
+	maxOpen      *prometheus.Desc
+	open         *prometheus.Desc
+	inUse        *prometheus.Desc
+	idle         *prometheus.Desc
+	waitCount    *prometheus.Desc
+	waitDuration *prometheus.Desc
+	closed       *prometheus.Desc
+}
+
+// RegisterDBStats registers a collector exporting the pool statistics of db
+func RegisterDBStats(db *sql.DB) error {
+	return prometheus.Register(&dbStatsCollector{
+		db: db,
+		maxOpen: prometheus.NewDesc("catalog_db_max_open_connections",
+			"Maximum number of open connections to the database", nil, nil),
+		open: prometheus.NewDesc("catalog_db_open_connections",
+			"Number of established connections, both in use and idle", nil, nil),
+		inUse: prometheus.NewDesc("catalog_db_in_use_connections",
+			"Number of connections currently in use", nil, nil),
+		idle: prometheus.NewDesc("catalog_db_idle_connections",
+			"Number of idle connections", nil, nil),
+		waitCount: prometheus.NewDesc("catalog_db_wait_count_total",
+			"Total number of connections waited for because the pool was exhausted", nil, nil),
+		waitDuration: prometheus.NewDesc("catalog_db_wait_duration_seconds_total",
+			"Total time spent waiting for a connection", nil, nil),
+		closed: prometheus.NewDesc("catalog_db_connections_closed_total",
+			"Total number of connections closed by the pool, by reason", []string{"reason"}, nil),
+	})
+}
+
+// Describe implements prometheus.Collector
+func (c *dbStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.maxOpen
+	ch <- c.open
+	ch <- c.inUse
+	ch <- c.idle
+	ch <- c.waitCount
+	ch <- c.waitDuration
+	ch <- c.closed
+}
+
+// Collect implements prometheus.Collector
+func (c *dbStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.db.Stats()
+
+	ch <- prometheus.MustNewConstMetric(c.maxOpen, prometheus.GaugeValue, float64(stats.MaxOpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.open, prometheus.GaugeValue, float64(stats.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.inUse, prometheus.GaugeValue, float64(stats.InUse))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(stats.Idle))
+	ch <- prometheus.MustNewConstMetric(c.waitCount, prometheus.CounterValue, float64(stats.WaitCount))
+	ch <- prometheus.MustNewConstMetric(c.waitDuration, prometheus.CounterValue, stats.WaitDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.closed, prometheus.CounterValue, float64(stats.MaxIdleClosed), "max_idle")
+	ch <- prometheus.MustNewConstMetric(c.closed, prometheus.CounterValue, float64(stats.MaxIdleTimeClosed), "max_idle_time")
+	ch <- prometheus.MustNewConstMetric(c.closed, prometheus.CounterValue, float64(stats.MaxLifetimeClosed), "max_lifetime")
+}
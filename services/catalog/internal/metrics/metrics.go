@@ -1,59 +1,132 @@
 package metrics
 
 import (
+	"context"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 )
 
-// HTTPMetrics holds all HTTP-related Prometheus metrics
+// HTTPMetrics holds the HTTP request metrics. They are OpenTelemetry instruments, exported
+// over OTLP and bridged to Prometheus under the same names as before: the bridge appends
+// _total to counters and _seconds to durations.
 type HTTPMetrics struct {
+	RequestsTotal    metric.Int64Counter
+	RequestDuration  metric.Float64Histogram
+	RequestsInFlight metric.Int64UpDownCounter
+}
+
+// NewHTTPMetrics creates the HTTP metrics on the global MeterProvider.
+// Instruments created before tracing.SetupMetrics are forwarded once it runs.
+func NewHTTPMetrics() *HTTPMetrics {
+	meter := otel.Meter("catalog-service")
+	m := &HTTPMetrics{}
+
+	var err error
+	m.RequestsTotal, err = meter.Int64Counter("catalog_http_requests",
+		metric.WithDescription("Total number of HTTP requests processed by the catalog service"),
+	)
+	handleErr(err)
+
+	m.RequestDuration, err = meter.Float64Histogram("catalog_http_request_duration",
+		metric.WithDescription("Duration of HTTP requests processed by the catalog service"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(prometheus.DefBuckets...), // .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10
+	)
+	handleErr(err)
+
+	m.RequestsInFlight, err = meter.Int64UpDownCounter("catalog_http_requests_in_flight",
+		metric.WithDescription("Current number of HTTP requests being processed by the catalog service"),
+	)
+	handleErr(err)
+
+	return m
+}
+
+// handleErr reports an instrument that could not be created; the returned no-op instrument is still safe to use
+func handleErr(err error) {
+	if err != nil {
+		otel.Handle(err)
+	}
+}
+
+// RecordRequest records metrics for an HTTP request. ctx carries the request span, which
+// becomes the exemplar of the duration bucket when the trace is sampled.
+func (m *HTTPMetrics) RecordRequest(ctx context.Context, method, path, statusCode string, duration float64) {
+	// Record request count
+	m.RequestsTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("method", method),
+		attribute.String("path", path),
+		attribute.String("status_code", statusCode),
+	))
+
+	// Record request duration
+	m.RequestDuration.Record(ctx, duration, metric.WithAttributes(
+		attribute.String("method", method),
+		attribute.String("path", path),
+	))
+}
+
+// IncInFlight increments the in-flight requests counter
+func (m *HTTPMetrics) IncInFlight(ctx context.Context) {
+	m.RequestsInFlight.Add(ctx, 1)
+}
+
+// DecInFlight decrements the in-flight requests counter
+func (m *HTTPMetrics) DecInFlight(ctx context.Context) {
+	m.RequestsInFlight.Add(ctx, -1)
+}
+
+// GRPCMetrics holds all gRPC-related Prometheus metrics, mirroring HTTPMetrics
+type GRPCMetrics struct {
 	RequestsTotal    *prometheus.CounterVec
 	RequestDuration  *prometheus.HistogramVec
 	RequestsInFlight prometheus.Gauge
 }
 
-// NewHTTPMetrics creates and registers HTTP metrics
-func NewHTTPMetrics() *HTTPMetrics {
-	return &HTTPMetrics{
+// NewGRPCMetrics creates and registers gRPC metrics
+func NewGRPCMetrics() *GRPCMetrics {
+	return &GRPCMetrics{
 		RequestsTotal: promauto.NewCounterVec(
 			prometheus.CounterOpts{
-				Name: "catalog_http_requests_total",
-				Help: "Total number of HTTP requests processed by the catalog service",
+				Name: "catalog_grpc_requests_total",
+				Help: "Total number of gRPC requests processed by the catalog service",
 			},
-			[]string{"method", "path", "status_code"},
+			[]string{"method", "code"},
 		),
 		RequestDuration: promauto.NewHistogramVec(
 			prometheus.HistogramOpts{
-				Name:    "catalog_http_request_duration_seconds",
-				Help:    "Duration of HTTP requests processed by the catalog service",
-				Buckets: prometheus.DefBuckets, // Default buckets: .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10
+				Name:    "catalog_grpc_request_duration_seconds",
+				Help:    "Duration of gRPC requests processed by the catalog service",
+				Buckets: prometheus.DefBuckets,
 			},
-			[]string{"method", "path"},
+			[]string{"method"},
 		),
 		RequestsInFlight: promauto.NewGauge(
 			prometheus.GaugeOpts{
-				Name: "catalog_http_requests_in_flight",
-				Help: "Current number of HTTP requests being processed by the catalog service",
+				Name: "catalog_grpc_requests_in_flight",
+				Help: "Current number of gRPC requests being processed by the catalog service",
 			},
 		),
 	}
 }
 
-// RecordRequest records metrics for an HTTP request
-func (m *HTTPMetrics) RecordRequest(method, path, statusCode string, duration float64) {
-	// Record request count
-	m.RequestsTotal.WithLabelValues(method, path, statusCode).Inc()
-
-	// Record request duration
-	m.RequestDuration.WithLabelValues(method, path).Observe(duration)
+// RecordRequest records metrics for a gRPC request. method is the full method name,
+// e.g. "/catalog.v1.CatalogService/GetProduct", and code the gRPC status code name.
+func (m *GRPCMetrics) RecordRequest(method, code string, duration float64) {
+	m.RequestsTotal.WithLabelValues(method, code).Inc()
+	m.RequestDuration.WithLabelValues(method).Observe(duration)
 }
 
 // IncInFlight increments the in-flight requests counter
-func (m *HTTPMetrics) IncInFlight() {
+func (m *GRPCMetrics) IncInFlight() {
 	m.RequestsInFlight.Inc()
 }
 
 // DecInFlight decrements the in-flight requests counter
-func (m *HTTPMetrics) DecInFlight() {
+func (m *GRPCMetrics) DecInFlight() {
 	m.RequestsInFlight.Dec()
 }
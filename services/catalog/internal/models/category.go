@@ -0,0 +1,476 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"catalog-service/internal/db"
+	"catalog-service/internal/logger"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Category represents a node in the category tree
+type Category struct {
+	ID          int       `json:"id" db:"id"`
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description" db:"description"`
+	ParentID    *int      `json:"parent_id" db:"parent_id"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CategoryCreateRequest represents the request to create a new category
+type CategoryCreateRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	ParentID    *int   `json:"parent_id,omitempty"`
+}
+
+// CategoryUpdateRequest represents the request to update a category.
+// A parent_id of 0 moves the category to the top level.
+type CategoryUpdateRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	ParentID    *int    `json:"parent_id,omitempty"`
+}
+
+// CategoryProductsRequest represents the request to link products to a category
+type CategoryProductsRequest struct {
+	ProductIDs []int `json:"product_ids" binding:"required,min=1"`
+}
+
+// CategoryResponse represents the response when returning a category
+type CategoryResponse struct {
+	ID          int                `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	ParentID    *int               `json:"parent_id"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+	Children    []CategoryResponse `json:"children,omitempty"`
+}
+
+// categoryColumns is the column list every category query selects, in scanCategory order
+const categoryColumns = `id, name, description, parent_id, created_at, updated_at`
+
+// categorySubtreeQuery returns a query selecting the ids of the category identified
+// by the given placeholder (e.g. "$1") and all of its descendants
+func categorySubtreeQuery(placeholder string) string {
+	return `
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM categories WHERE id = ` + placeholder + `
+			UNION
+			SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+		)
+		SELECT id FROM subtree`
+}
+
+// scanCategory scans a row selected with categoryColumns into a Category
+func scanCategory(row rowScanner, category *Category) error {
+	var parentID sql.NullInt64
+	err := row.Scan(
+		&category.ID,
+		&category.Name,
+		&category.Description,
+		&parentID,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	category.ParentID = nil
+	if parentID.Valid {
+		id := int(parentID.Int64)
+		category.ParentID = &id
+	}
+	return nil
+}
+
+// categoryConstraintError maps Postgres constraint violations on insert or update to category errors
+func categoryConstraintError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return nil
+	}
+
+	switch pqErr.Code {
+	case "23505": // unique_violation
+		return ErrCategoryExists
+	case "23503": // foreign_key_violation
+		return ErrParentCategoryMissing
+	}
+	return nil
+}
+
+// CategoryService handles database operations for categories
+type CategoryService struct {
+	db *sql.DB
+}
+
+// NewCategoryService creates a new category service
+func NewCategoryService(db *sql.DB) *CategoryService {
+	return &CategoryService{db: db}
+}
+
+// CreateCategory creates a new category in the database
+func (s *CategoryService) CreateCategory(ctx context.Context, req CategoryCreateRequest) (*Category, error) {
+	dbCtx := db.WithQuery(ctx, "create_category", "categories")
+	span := trace.SpanFromContext(ctx)
+
+	span.SetAttributes(
+		attribute.String("category.name", req.Name),
+	)
+
+	query := `
+		INSERT INTO categories (name, description, parent_id)
+		VALUES ($1, $2, $3)
+		RETURNING ` + categoryColumns
+
+	var category Category
+	err := scanCategory(s.db.QueryRowContext(dbCtx, query, req.Name, req.Description, req.ParentID), &category)
+	if err != nil {
+		if constraintErr := categoryConstraintError(err); constraintErr != nil {
+			span.SetAttributes(attribute.String("db.result", "constraint_violation"))
+			return nil, constraintErr
+		}
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
+			"component": "category",
+			"action":    "create",
+			"name":      req.Name,
+		}).Error("Error creating category")
+		return nil, dbError("failed to create category", err)
+	}
+
+	span.SetAttributes(attribute.Int("category.id", category.ID))
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"component":   "category",
+		"action":      "create",
+		"category_id": category.ID,
+		"name":        category.Name,
+	}).Info("Created category")
+
+	return &category, nil
+}
+
+// GetCategory retrieves a category by ID
+func (s *CategoryService) GetCategory(ctx context.Context, id int) (*Category, error) {
+	dbCtx := db.WithQuery(ctx, "get_category", "categories")
+	span := trace.SpanFromContext(ctx)
+
+	span.SetAttributes(
+		attribute.Int("category.id", id),
+	)
+
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE id = $1`
+
+	var category Category
+	err := scanCategory(s.db.QueryRowContext(dbCtx, query, id), &category)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			span.SetAttributes(attribute.String("db.result", "not_found"))
+			return nil, ErrCategoryNotFound
+		}
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
+			"component":   "category",
+			"action":      "get",
+			"category_id": id,
+		}).Error("Error getting category")
+		return nil, dbError("failed to get category", err)
+	}
+
+	span.SetAttributes(attribute.String("db.result", "found"))
+
+	return &category, nil
+}
+
+// GetAllCategories retrieves every category as a flat list ordered by name
+func (s *CategoryService) GetAllCategories(ctx context.Context) ([]Category, error) {
+	dbCtx := db.WithQuery(ctx, "get_all_categories", "categories")
+	span := trace.SpanFromContext(ctx)
+
+	query := `SELECT ` + categoryColumns + ` FROM categories ORDER BY name, id`
+
+	rows, err := s.db.QueryContext(dbCtx, query)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
+			"component": "category",
+			"action":    "list",
+		}).Error("Error getting categories")
+		return nil, dbError("failed to get categories", err)
+	}
+	defer rows.Close()
+
+	var categories []Category
+	for rows.Next() {
+		var category Category
+		if err := scanCategory(rows, &category); err != nil {
+			return nil, dbError("failed to scan category", err)
+		}
+		categories = append(categories, category)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbError("failed to iterate categories", err)
+	}
+
+	span.SetAttributes(attribute.Int("categories.count", len(categories)))
+
+	return categories, nil
+}
+
+// UpdateCategory updates an existing category. Moving a category under itself
+// or one of its own descendants is rejected so the tree can never contain a cycle.
+func (s *CategoryService) UpdateCategory(ctx context.Context, id int, req CategoryUpdateRequest) (*Category, error) {
+	// First, get the current category
+	current, err := s.GetCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update only the fields that were provided
+	if req.Name != nil {
+		current.Name = *req.Name
+	}
+	if req.Description != nil {
+		current.Description = *req.Description
+	}
+	if req.ParentID != nil {
+		if *req.ParentID == 0 {
+			current.ParentID = nil
+		} else {
+			current.ParentID = req.ParentID
+		}
+	}
+
+	dbCtx := db.WithQuery(ctx, "update_category", "categories")
+	span := trace.SpanFromContext(ctx)
+
+	span.SetAttributes(
+		attribute.Int("category.id", id),
+	)
+
+	if current.ParentID != nil {
+		var createsCycle bool
+		cycleQuery := `SELECT $2::int IN (` + categorySubtreeQuery("$1") + `)`
+		if err := s.db.QueryRowContext(dbCtx, cycleQuery, id, *current.ParentID).Scan(&createsCycle); err != nil {
+			return nil, dbError("failed to check category tree", err)
+		}
+		if createsCycle {
+			span.SetAttributes(attribute.String("db.result", "cycle"))
+			return nil, ErrCategoryCycle
+		}
+	}
+
+	query := `
+		UPDATE categories
+		SET name = $1, description = $2, parent_id = $3, updated_at = NOW()
+		WHERE id = $4
+		RETURNING ` + categoryColumns
+
+	var category Category
+	err = scanCategory(s.db.QueryRowContext(dbCtx, query, current.Name, current.Description, current.ParentID, id), &category)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			span.SetAttributes(attribute.String("db.result", "not_found"))
+			return nil, ErrCategoryNotFound
+		}
+		if constraintErr := categoryConstraintError(err); constraintErr != nil {
+			span.SetAttributes(attribute.String("db.result", "constraint_violation"))
+			return nil, constraintErr
+		}
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
+			"component":   "category",
+			"action":      "update",
+			"category_id": id,
+		}).Error("Error updating category")
+		return nil, dbError("failed to update category", err)
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"component":   "category",
+		"action":      "update",
+		"category_id": category.ID,
+		"name":        category.Name,
+	}).Info("Updated category")
+
+	return &category, nil
+}
+
+// DeleteCategory deletes a category by ID. Categories with sub-categories cannot be deleted.
+func (s *CategoryService) DeleteCategory(ctx context.Context, id int) error {
+	dbCtx := db.WithQuery(ctx, "delete_category", "categories")
+	span := trace.SpanFromContext(ctx)
+
+	span.SetAttributes(
+		attribute.Int("category.id", id),
+	)
+
+	result, err := s.db.ExecContext(dbCtx, `DELETE FROM categories WHERE id = $1`, id)
+	if err != nil {
+		// categories.parent_id is ON DELETE RESTRICT, so a parent cannot be removed before its children
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			span.SetAttributes(attribute.String("db.result", "constraint_violation"))
+			return ErrCategoryHasChildren
+		}
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
+			"component":   "category",
+			"action":      "delete",
+			"category_id": id,
+		}).Error("Error deleting category")
+		return dbError("failed to delete category", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dbError("failed to get rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		span.SetAttributes(attribute.String("db.result", "not_found"))
+		return ErrCategoryNotFound
+	}
+
+	span.SetAttributes(attribute.String("db.result", "deleted"))
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"component":   "category",
+		"action":      "delete",
+		"category_id": id,
+	}).Info("Deleted category")
+
+	return nil
+}
+
+// AddProducts links products to a category. Links that already exist are ignored.
+func (s *CategoryService) AddProducts(ctx context.Context, id int, productIDs []int) error {
+	dbCtx := db.WithQuery(ctx, "add_category_products", "product_categories")
+	span := trace.SpanFromContext(ctx)
+
+	span.SetAttributes(
+		attribute.Int("category.id", id),
+		attribute.Int("products.count", len(productIDs)),
+	)
+
+	query := `
+		INSERT INTO product_categories (product_id, category_id)
+		SELECT unnest($1::int[]), $2
+		ON CONFLICT DO NOTHING`
+
+	if _, err := s.db.ExecContext(dbCtx, query, pq.Array(productIDs), id); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			span.SetAttributes(attribute.String("db.result", "constraint_violation"))
+			if pqErr.Constraint == "product_categories_category_id_fkey" {
+				return ErrCategoryNotFound
+			}
+			return ErrProductNotFound
+		}
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
+			"component":   "category",
+			"action":      "add_products",
+			"category_id": id,
+		}).Error("Error linking products to category")
+		return dbError("failed to link products", err)
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"component":   "category",
+		"action":      "add_products",
+		"category_id": id,
+		"product_ids": productIDs,
+	}).Info("Linked products to category")
+
+	return nil
+}
+
+// RemoveProduct unlinks a product from a category
+func (s *CategoryService) RemoveProduct(ctx context.Context, id, productID int) error {
+	dbCtx := db.WithQuery(ctx, "remove_category_product", "product_categories")
+	span := trace.SpanFromContext(ctx)
+
+	span.SetAttributes(
+		attribute.Int("category.id", id),
+		attribute.Int("product.id", productID),
+	)
+
+	query := `DELETE FROM product_categories WHERE category_id = $1 AND product_id = $2`
+
+	result, err := s.db.ExecContext(dbCtx, query, id, productID)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
+			"component":   "category",
+			"action":      "remove_product",
+			"category_id": id,
+			"product_id":  productID,
+		}).Error("Error unlinking product from category")
+		return dbError("failed to unlink product", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dbError("failed to get rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		span.SetAttributes(attribute.String("db.result", "not_found"))
+		return ErrProductNotInCategory
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"component":   "category",
+		"action":      "remove_product",
+		"category_id": id,
+		"product_id":  productID,
+	}).Info("Unlinked product from category")
+
+	return nil
+}
+
+// ToResponse converts a Category to a CategoryResponse
+func (c *Category) ToResponse() CategoryResponse {
+	return CategoryResponse{
+		ID:          c.ID,
+		Name:        c.Name,
+		Description: c.Description,
+		ParentID:    c.ParentID,
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+	}
+}
+
+// BuildCategoryTree nests a flat list of categories under their parents.
+// The order of the input list is kept at every level of the tree.
+func BuildCategoryTree(categories []Category) []CategoryResponse {
+	childrenOf := make(map[int][]Category)
+	var roots []Category
+	for _, category := range categories {
+		if category.ParentID == nil {
+			roots = append(roots, category)
+		} else {
+			childrenOf[*category.ParentID] = append(childrenOf[*category.ParentID], category)
+		}
+	}
+
+	var build func(nodes []Category) []CategoryResponse
+	build = func(nodes []Category) []CategoryResponse {
+		responses := make([]CategoryResponse, 0, len(nodes))
+		for _, node := range nodes {
+			response := node.ToResponse()
+			response.Children = build(childrenOf[node.ID])
+			responses = append(responses, response)
+		}
+		return responses
+	}
+
+	return build(roots)
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ProductPage selects a page of products, either by offset or after a keyset cursor.
+// When After is set, Offset is ignored.
+type ProductPage struct {
+	Offset int
+	Limit  int
+	After  *ProductCursor
+}
+
+// ProductCursor marks the last product of a page for keyset pagination.
+// Clients only ever see it as an opaque string.
+type ProductCursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v,omitempty"`
+	ID    int    `json:"id"`
+}
+
+// productSortCasts gives the SQL type of each sort column, so the cursor value
+// (always stored as a string) is compared with the right type
+var productSortCasts = map[string]string{
+	"price":  "numeric",
+	"name":   "text",
+	"stock":  "integer",
+	"newest": "timestamptz",
+}
+
+// NewProductCursor returns the cursor pointing just after product for the given sort
+func NewProductCursor(product Product, sort ProductSort) *ProductCursor {
+	cursor := &ProductCursor{Sort: sort.String(), ID: product.ID}
+
+	switch sort.Field {
+	case "price":
+		cursor.Value = strconv.FormatFloat(product.Price, 'f', -1, 64)
+	case "name":
+		cursor.Value = product.Name
+	case "stock":
+		cursor.Value = strconv.Itoa(product.StockQty)
+	case "newest":
+		cursor.Value = product.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+
+	return cursor
+}
+
+// Encode returns the opaque string form of the cursor
+func (c *ProductCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeProductCursor parses a cursor produced by Encode
+func DecodeProductCursor(encoded string) (*ProductCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor ProductCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID < 1 {
+		return nil, ErrInvalidCursor
+	}
+
+	sort, err := cursor.ProductSort()
+	if err != nil || !validCursorValue(sort.Field, cursor.Value) {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+// validCursorValue reports whether value can be cast to the type of the sort column,
+// so a tampered cursor is rejected here instead of failing the query
+func validCursorValue(field, value string) bool {
+	switch field {
+	case "price":
+		price, err := strconv.ParseFloat(value, 64)
+		return err == nil && !math.IsNaN(price) && !math.IsInf(price, 0)
+	case "name":
+		// Postgres text cannot hold NUL bytes or invalid UTF-8
+		return utf8.ValidString(value) && !strings.ContainsRune(value, 0)
+	case "stock":
+		_, err := strconv.ParseInt(value, 10, 32)
+		return err == nil
+	case "newest":
+		_, err := time.Parse(time.RFC3339Nano, value)
+		return err == nil
+	default:
+		// Sorting by id only uses the cursor's id
+		return value == ""
+	}
+}
+
+// ProductSort returns the sort order the cursor was created for
+func (c *ProductCursor) ProductSort() (ProductSort, error) {
+	field, order, _ := strings.Cut(c.Sort, ":")
+	return ParseProductSort(field, order)
+}
+
+// keysetCondition returns the condition selecting rows after the cursor in the given sort order.
+// orderBy always ends with id in the same direction as the sort column, so a row
+// comparison on (column, id) matches the ORDER BY exactly.
+func (c *ProductCursor) keysetCondition(sort ProductSort, args *queryArgs) string {
+	op := ">"
+	if sort.Desc {
+		op = "<"
+	}
+
+	if sort.column() == "id" {
+		return "id " + op + " " + args.add(c.ID)
+	}
+
+	value := args.add(c.Value) + "::" + productSortCasts[sort.Field]
+	return "(" + sort.column() + ", id) " + op + " (" + value + ", " + args.add(c.ID) + ")"
+}
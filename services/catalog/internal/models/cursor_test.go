@@ -0,0 +1,166 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestProductCursorRoundTrip(t *testing.T) {
+	product := Product{
+		ID:        42,
+		Name:      "Widget, \"deluxe\"",
+		Price:     19.99,
+		StockQty:  7,
+		CreatedAt: time.Date(2025, 3, 14, 15, 9, 26, 535897000, time.FixedZone("CET", 3600)),
+	}
+
+	tests := []struct {
+		field string
+		order string
+		value string
+	}{
+		{field: "id", order: "asc", value: ""},
+		{field: "id", order: "desc", value: ""},
+		{field: "price", order: "asc", value: "19.99"},
+		{field: "name", order: "desc", value: "Widget, \"deluxe\""},
+		{field: "stock", order: "", value: "7"},
+		{field: "newest", order: "", value: "2025-03-14T14:09:26.535897Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field+":"+tt.order, func(t *testing.T) {
+			sort, err := ParseProductSort(tt.field, tt.order)
+			if err != nil {
+				t.Fatalf("ParseProductSort() error = %v", err)
+			}
+
+			cursor := NewProductCursor(product, sort)
+			if cursor.Value != tt.value {
+				t.Errorf("cursor value = %q, want %q", cursor.Value, tt.value)
+			}
+
+			decoded, err := DecodeProductCursor(cursor.Encode())
+			if err != nil {
+				t.Fatalf("DecodeProductCursor() error = %v", err)
+			}
+			if *decoded != *cursor {
+				t.Errorf("decoded cursor = %+v, want %+v", *decoded, *cursor)
+			}
+
+			decodedSort, err := decoded.ProductSort()
+			if err != nil {
+				t.Fatalf("ProductSort() error = %v", err)
+			}
+			if decodedSort != sort {
+				t.Errorf("cursor sort = %+v, want %+v", decodedSort, sort)
+			}
+		})
+	}
+}
+
+func TestDecodeProductCursorRejectsTampering(t *testing.T) {
+	encode := func(json string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(json))
+	}
+
+	tests := []struct {
+		name    string
+		encoded string
+	}{
+		{name: "empty", encoded: ""},
+		{name: "not base64", encoded: "not a cursor!"},
+		{name: "not JSON", encoded: encode("id=1")},
+		{name: "truncated JSON", encoded: encode(`{"s":"id:asc","id":1`)},
+		{name: "missing id", encoded: encode(`{"s":"id:asc"}`)},
+		{name: "zero id", encoded: encode(`{"s":"id:asc","id":0}`)},
+		{name: "negative id", encoded: encode(`{"s":"id:asc","id":-5}`)},
+		{name: "id as string", encoded: encode(`{"s":"id:asc","id":"1"}`)},
+		{name: "unknown sort field", encoded: encode(`{"s":"password:asc","id":1}`)},
+		{name: "SQL as sort field", encoded: encode(`{"s":"id; DROP TABLE products:asc","id":1}`)},
+		{name: "unknown sort order", encoded: encode(`{"s":"price:sideways","v":"1","id":1}`)},
+		{name: "value with id sort", encoded: encode(`{"s":"id:asc","v":"1","id":1}`)},
+		{name: "price not a number", encoded: encode(`{"s":"price:asc","v":"abc","id":1}`)},
+		{name: "price missing", encoded: encode(`{"s":"price:asc","id":1}`)},
+		{name: "price NaN", encoded: encode(`{"s":"price:asc","v":"NaN","id":1}`)},
+		{name: "price infinite", encoded: encode(`{"s":"price:desc","v":"Infinity","id":1}`)},
+		{name: "stock not an integer", encoded: encode(`{"s":"stock:asc","v":"1.5","id":1}`)},
+		{name: "stock out of range", encoded: encode(`{"s":"stock:asc","v":"99999999999","id":1}`)},
+		{name: "newest not a time", encoded: encode(`{"s":"newest:desc","v":"yesterday","id":1}`)},
+		{name: "newest without zone", encoded: encode(`{"s":"newest:desc","v":"2024-01-02T03:04:05","id":1}`)},
+		{name: "name with NUL byte", encoded: encode(`{"s":"name:asc","v":"a\u0000b","id":1}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := DecodeProductCursor(tt.encoded)
+			if !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("DecodeProductCursor() = %+v, %v, want ErrInvalidCursor", cursor, err)
+			}
+			if !errors.Is(err, ErrValidation) {
+				t.Errorf("error %v is not a validation error", err)
+			}
+		})
+	}
+}
+
+func TestProductCursorKeysetCondition(t *testing.T) {
+	tests := []struct {
+		name      string
+		cursor    ProductCursor
+		sort      ProductSort
+		condition string
+		args      []interface{}
+	}{
+		{
+			name:      "id ascending",
+			cursor:    ProductCursor{Sort: "id:asc", ID: 10},
+			sort:      ProductSort{Field: "id"},
+			condition: "id > $1",
+			args:      []interface{}{10},
+		},
+		{
+			name:      "id descending",
+			cursor:    ProductCursor{Sort: "id:desc", ID: 10},
+			sort:      ProductSort{Field: "id", Desc: true},
+			condition: "id < $1",
+			args:      []interface{}{10},
+		},
+		{
+			name:      "price ascending",
+			cursor:    ProductCursor{Sort: "price:asc", Value: "19.99", ID: 3},
+			sort:      ProductSort{Field: "price"},
+			condition: "(price, id) > ($1::numeric, $2)",
+			args:      []interface{}{"19.99", 3},
+		},
+		{
+			name:      "newest",
+			cursor:    ProductCursor{Sort: "newest:desc", Value: "2025-03-14T14:09:26Z", ID: 3},
+			sort:      ProductSort{Field: "newest", Desc: true},
+			condition: "(created_at, id) < ($1::timestamptz, $2)",
+			args:      []interface{}{"2025-03-14T14:09:26Z", 3},
+		},
+		{
+			// A tampered value is only ever a bind parameter, never part of the SQL
+			name:      "tampered value",
+			cursor:    ProductCursor{Sort: "name:asc", Value: "x') OR 1=1 --", ID: 3},
+			sort:      ProductSort{Field: "name"},
+			condition: "(name, id) > ($1::text, $2)",
+			args:      []interface{}{"x') OR 1=1 --", 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := &queryArgs{}
+			if got := tt.cursor.keysetCondition(tt.sort, args); got != tt.condition {
+				t.Errorf("keysetCondition() = %q, want %q", got, tt.condition)
+			}
+			if !slices.Equal(args.values, tt.args) {
+				t.Errorf("args = %v, want %v", args.values, tt.args)
+			}
+		})
+	}
+}
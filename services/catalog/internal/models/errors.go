@@ -0,0 +1,143 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Error kinds. Every error the services return for an expected condition matches one of
+// them with errors.Is; anything else is an internal error.
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrConflict    = errors.New("conflict")
+	ErrValidation  = errors.New("validation failed")
+	ErrUnavailable = errors.New("service unavailable")
+)
+
+// Specific errors, for callers that need to tell apart errors of the same kind
+var (
+	ErrProductNotFound       = newDomainError(ErrNotFound, "product not found")
+	ErrCategoryNotFound      = newDomainError(ErrNotFound, "category not found")
+	ErrReservationNotFound   = newDomainError(ErrNotFound, "reservation not found")
+	ErrProductNotInCategory  = newDomainError(ErrNotFound, "product is not in category")
+	ErrVersionMismatch       = newDomainError(ErrConflict, "product has been modified since it was last read")
+	ErrCategoryExists        = newDomainError(ErrConflict, "a category with this name already exists under the same parent")
+	ErrCategoryHasChildren   = newDomainError(ErrConflict, "category has sub-categories and cannot be deleted")
+	ErrReservationNotActive  = newDomainError(ErrConflict, "reservation is already committed, released or expired")
+	ErrReservationExpired    = newDomainError(ErrConflict, "reservation expired")
+	ErrParentCategoryMissing = newDomainError(ErrValidation, "parent category not found")
+	ErrCategoryCycle         = newDomainError(ErrValidation, "category cannot be moved under itself or its sub-categories")
+	ErrNoSearchTerms         = newDomainError(ErrValidation, "search query must contain at least one letter or digit")
+	ErrInvalidCursor         = newDomainError(ErrValidation, "invalid cursor")
+	ErrStockBelowReserved    = newDomainError(ErrConflict, "stock cannot drop below the quantity held by active reservations")
+)
+
+// domainError is an error with its own message that matches its kind with errors.Is
+type domainError struct {
+	kind    error
+	message string
+}
+
+func newDomainError(kind error, message string) error {
+	return &domainError{kind: kind, message: message}
+}
+
+func (e *domainError) Error() string { return e.message }
+
+func (e *domainError) Unwrap() error { return e.kind }
+
+// FieldError describes one invalid field of a request
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError reports the invalid fields of a request; it matches ErrValidation
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// NewValidationError returns a ValidationError for a single field
+func NewValidationError(field, code, message string) *ValidationError {
+	return &ValidationError{Fields: []FieldError{{Field: field, Code: code, Message: message}}}
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		parts = append(parts, f.Field+": "+f.Message)
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+func (e *ValidationError) Unwrap() error { return ErrValidation }
+
+// CHECK constraints on products that keep stock consistent (migration 0009)
+const (
+	constraintStockNonNegative    = "products_stock_quantity_non_negative"
+	constraintStockCoversReserved = "products_stock_covers_reserved"
+)
+
+// isStockViolation reports whether err is a violation of the stock CHECK constraints
+func isStockViolation(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code.Name() != "check_violation" {
+		return false
+	}
+	return pqErr.Constraint == constraintStockNonNegative || pqErr.Constraint == constraintStockCoversReserved
+}
+
+// isOutOfRange reports whether err is a value that does not fit its numeric column
+func isOutOfRange(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code.Name() == "numeric_value_out_of_range"
+}
+
+// unavailableError marks a database error caused by the database being unreachable or overloaded
+type unavailableError struct {
+	err error
+}
+
+func (e *unavailableError) Error() string { return e.err.Error() }
+
+func (e *unavailableError) Unwrap() []error { return []error{ErrUnavailable, e.err} }
+
+// dbError wraps a database error with what was being done, keeping the error chain.
+// Connection failures and timeouts also match ErrUnavailable.
+func dbError(action string, err error) error {
+	if isUnavailable(err) {
+		err = &unavailableError{err: err}
+	}
+	return fmt.Errorf("%s: %w", action, err)
+}
+
+// isUnavailable reports whether a database error means the database cannot serve requests right now
+func isUnavailable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code.Class() {
+		case "08", // connection_exception
+			"53", // insufficient_resources, e.g. too_many_connections
+			"57": // operator_intervention: query_canceled (statement timeout), admin_shutdown, cannot_connect_now
+			return true
+		}
+	}
+	return false
+}
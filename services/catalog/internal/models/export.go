@@ -0,0 +1,95 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"catalog-service/internal/db"
+	"catalog-service/internal/logger"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// exportBatchSize is how many rows each FETCH pulls from the export cursor
+const exportBatchSize = 500
+
+// ExportProducts streams every product matching the filter to fn, in the filter's sort order.
+// Rows are read through a server-side cursor in batches, so memory use does not grow with the
+// catalog size, and the whole export sees one consistent snapshot. A progress event is added
+// to the span after each batch. If fn returns an error the export stops and returns it.
+func (s *ProductService) ExportProducts(ctx context.Context, filter ProductFilter, fn func(Product) error) (int, error) {
+	dbCtx := db.WithQuery(ctx, "export_products", "products")
+	span := trace.SpanFromContext(ctx)
+
+	span.SetAttributes(
+		attribute.Int("export.batch_size", exportBatchSize),
+	)
+	span.SetAttributes(filter.attributes()...)
+
+	tx, err := s.db.BeginTx(dbCtx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return 0, dbError("failed to begin transaction", err)
+	}
+	// Read-only, so rolling back just closes the cursor and ends the transaction
+	defer tx.Rollback()
+
+	args := &queryArgs{}
+	query := `DECLARE product_export NO SCROLL CURSOR FOR
+		SELECT ` + productColumns + ` FROM products` + filter.where(args) +
+		` ORDER BY ` + filter.Sort.orderBy()
+
+	if _, err := tx.ExecContext(dbCtx, query, args.values...); err != nil {
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
+			"component": "product",
+			"action":    "export",
+		}).Error("Error opening export cursor")
+		return 0, dbError("failed to open export cursor", err)
+	}
+
+	fetch := `FETCH ` + strconv.Itoa(exportBatchSize) + ` FROM product_export`
+	exported := 0
+	for {
+		rows, err := tx.QueryContext(dbCtx, fetch)
+		if err != nil {
+			return exported, dbError("failed to fetch products", err)
+		}
+
+		batch := 0
+		for rows.Next() {
+			var product Product
+			if err := scanProduct(rows, &product); err != nil {
+				rows.Close()
+				return exported, dbError("failed to scan product", err)
+			}
+			if err := fn(product); err != nil {
+				rows.Close()
+				span.SetAttributes(attribute.Int("export.rows", exported))
+				return exported, err
+			}
+			batch++
+			exported++
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return exported, dbError("failed to iterate products", err)
+		}
+
+		if batch == 0 {
+			break
+		}
+		span.AddEvent("export.progress", trace.WithAttributes(
+			attribute.Int("export.batch_rows", batch),
+			attribute.Int("export.rows", exported),
+		))
+		if batch < exportBatchSize {
+			break
+		}
+	}
+
+	span.SetAttributes(attribute.Int("export.rows", exported))
+
+	return exported, nil
+}
@@ -0,0 +1,174 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ProductFilter narrows down and orders the products returned by GetAllProducts
+type ProductFilter struct {
+	// CategoryID limits results to products in this category or any of its sub-categories
+	CategoryID   *int
+	MinPrice     *float64
+	MaxPrice     *float64
+	InStock      *bool
+	NameContains string
+	Sort         ProductSort
+}
+
+// ProductSort is a whitelisted sort order for product listings
+type ProductSort struct {
+	Field string
+	Desc  bool
+}
+
+// productSortColumns maps the public sort names to their database columns.
+// Only these columns can ever end up in an ORDER BY clause.
+var productSortColumns = map[string]string{
+	"id":     "id",
+	"price":  "price",
+	"name":   "name",
+	"stock":  "stock_quantity",
+	"newest": "created_at",
+}
+
+// ParseProductSort validates a sort field and an order ("asc" or "desc").
+// An empty field sorts by id; "newest" defaults to descending, everything else to ascending.
+func ParseProductSort(field, order string) (ProductSort, error) {
+	if field == "" {
+		field = "id"
+	}
+	if _, ok := productSortColumns[field]; !ok {
+		return ProductSort{}, NewValidationError("sort", CodeInvalid, fmt.Sprintf("unsupported sort field %q (use id, price, name, stock or newest)", field))
+	}
+
+	sort := ProductSort{Field: field, Desc: field == "newest"}
+	switch strings.ToLower(order) {
+	case "":
+	case "asc":
+		sort.Desc = false
+	case "desc":
+		sort.Desc = true
+	default:
+		return ProductSort{}, NewValidationError("order", CodeInvalid, fmt.Sprintf("unsupported sort order %q (use asc or desc)", order))
+	}
+
+	return sort, nil
+}
+
+// column returns the database column to sort by
+func (s ProductSort) column() string {
+	if column, ok := productSortColumns[s.Field]; ok {
+		return column
+	}
+	return "id"
+}
+
+// direction returns the SQL sort direction keyword
+func (s ProductSort) direction() string {
+	if s.Desc {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+// orderBy returns the ORDER BY expression. id is always the tie-breaker
+// so rows with equal sort values come back in a stable order.
+func (s ProductSort) orderBy() string {
+	if s.column() == "id" {
+		return "id " + s.direction()
+	}
+	return s.column() + " " + s.direction() + ", id " + s.direction()
+}
+
+// String returns the sort in "field:order" form for logs and span attributes
+func (s ProductSort) String() string {
+	field := s.Field
+	if field == "" {
+		field = "id"
+	}
+	return field + ":" + strings.ToLower(s.direction())
+}
+
+// queryArgs collects positional query arguments.
+// add returns the $N placeholder for a value, so values are never interpolated into SQL.
+type queryArgs struct {
+	values []interface{}
+}
+
+func (q *queryArgs) add(value interface{}) string {
+	q.values = append(q.values, value)
+	return "$" + strconv.Itoa(len(q.values))
+}
+
+// likeEscaper escapes LIKE wildcards so name_contains matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// conditions returns the SQL conditions for the filter, adding their values to args
+func (f ProductFilter) conditions(args *queryArgs) []string {
+	var conditions []string
+
+	if f.CategoryID != nil {
+		conditions = append(conditions, `EXISTS (
+			SELECT 1 FROM product_categories pc
+			WHERE pc.product_id = products.id
+			AND pc.category_id IN (`+categorySubtreeQuery(args.add(*f.CategoryID))+`)
+		)`)
+	}
+	if f.MinPrice != nil {
+		conditions = append(conditions, "price >= "+args.add(*f.MinPrice))
+	}
+	if f.MaxPrice != nil {
+		conditions = append(conditions, "price <= "+args.add(*f.MaxPrice))
+	}
+	if f.InStock != nil {
+		// In stock means some stock is left after active reservations
+		if *f.InStock {
+			conditions = append(conditions, "stock_quantity - reserved_quantity > 0")
+		} else {
+			conditions = append(conditions, "stock_quantity - reserved_quantity <= 0")
+		}
+	}
+	if f.NameContains != "" {
+		conditions = append(conditions, "name ILIKE '%' || "+args.add(likeEscaper.Replace(f.NameContains))+" || '%'")
+	}
+
+	return conditions
+}
+
+// where returns the WHERE clause for the filter, or an empty string when nothing is filtered
+func (f ProductFilter) where(args *queryArgs) string {
+	conditions := f.conditions(args)
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+// attributes returns span attributes describing the active filters and sort order
+func (f ProductFilter) attributes() []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("query.sort", f.Sort.String()),
+	}
+
+	if f.CategoryID != nil {
+		attrs = append(attrs, attribute.Int("filter.category_id", *f.CategoryID))
+	}
+	if f.MinPrice != nil {
+		attrs = append(attrs, attribute.Float64("filter.min_price", *f.MinPrice))
+	}
+	if f.MaxPrice != nil {
+		attrs = append(attrs, attribute.Float64("filter.max_price", *f.MaxPrice))
+	}
+	if f.InStock != nil {
+		attrs = append(attrs, attribute.Bool("filter.in_stock", *f.InStock))
+	}
+	if f.NameContains != "" {
+		attrs = append(attrs, attribute.String("filter.name_contains", f.NameContains))
+	}
+
+	return attrs
+}
@@ -0,0 +1,310 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"catalog-service/internal/db"
+	"catalog-service/internal/logger"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Import modes
+const (
+	// ImportAllOrNothing imports nothing if any row is rejected
+	ImportAllOrNothing = "all_or_nothing"
+	// ImportBestEffort imports the valid rows and reports the rejected ones
+	ImportBestEffort = "best_effort"
+)
+
+// ProductImportRow is one product read from an import file.
+// Rows with an external key update the product with that key if it exists.
+type ProductImportRow struct {
+	Line        int    `json:"-"`
+	ExternalKey string `json:"external_key"`
+	ProductCreateRequest
+}
+
+// ImportRowError reports why a row of an import file was rejected.
+// Fields lists the invalid fields when the row failed validation.
+type ImportRowError struct {
+	Line        int          `json:"line"`
+	ExternalKey string       `json:"external_key,omitempty"`
+	Message     string       `json:"error"`
+	Fields      []FieldError `json:"fields,omitempty"`
+}
+
+func (e *ImportRowError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// ImportRowSource yields the rows of an import file one at a time.
+// Next returns an *ImportRowError for a row that cannot be imported, io.EOF after
+// the last row, and any other error when the file itself cannot be read further.
+type ImportRowSource interface {
+	Next() (ProductImportRow, error)
+}
+
+// ImportReport summarizes a bulk import
+type ImportReport struct {
+	Mode      string           `json:"mode"`
+	Rows      int              `json:"rows"`
+	Inserted  int              `json:"inserted"`
+	Updated   int              `json:"updated"`
+	Rejected  []ImportRowError `json:"rejected"`
+	Committed bool             `json:"committed"`
+}
+
+// importColumns are the staging table columns, in the order rows are copied
+var importColumns = []string{"line", "external_key", "name", "description", "price", "stock_quantity"}
+
+// ImportProducts streams rows into a staging table with COPY and merges them into products
+// in one transaction. Rows with an external key that already exists update that product;
+// all other rows are inserted. In all-or-nothing mode a single rejected row rolls back the
+// whole import, but the file is still read to the end so every rejected row is reported.
+func (s *ProductService) ImportProducts(ctx context.Context, source ImportRowSource, mode string) (*ImportReport, error) {
+	dbCtx := db.WithQuery(ctx, "import_products", "products")
+	span := trace.SpanFromContext(ctx)
+
+	span.SetAttributes(
+		attribute.String("import.mode", mode),
+	)
+
+	report := &ImportReport{Mode: mode, Rejected: []ImportRowError{}}
+
+	tx, err := s.db.BeginTx(dbCtx, nil)
+	if err != nil {
+		return nil, dbError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(dbCtx, `
+		CREATE TEMP TABLE product_import (
+			line INTEGER NOT NULL,
+			external_key VARCHAR(100),
+			name VARCHAR(255) NOT NULL,
+			description TEXT,
+			price DECIMAL(10,2) NOT NULL,
+			stock_quantity INTEGER NOT NULL
+		) ON COMMIT DROP`); err != nil {
+		return nil, dbError("failed to create import table", err)
+	}
+
+	stmt, err := tx.PrepareContext(dbCtx, pq.CopyIn("product_import", importColumns...))
+	if err != nil {
+		return nil, dbError("failed to start copy", err)
+	}
+	defer stmt.Close()
+
+	// An external key may appear only once per file, otherwise the result would depend on row order
+	seenKeys := make(map[string]int)
+
+	for {
+		row, err := source.Next()
+		if err == io.EOF {
+			break
+		}
+
+		var rowErr *ImportRowError
+		if errors.As(err, &rowErr) {
+			report.Rows++
+			report.Rejected = append(report.Rejected, *rowErr)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		report.Rows++
+		if err := row.Validate(); err != nil {
+			rejected := ImportRowError{Line: row.Line, ExternalKey: row.ExternalKey, Message: err.Error()}
+			var validationErr *ValidationError
+			if errors.As(err, &validationErr) {
+				rejected.Fields = validationErr.Fields
+			}
+			report.Rejected = append(report.Rejected, rejected)
+			continue
+		}
+		if row.ExternalKey != "" {
+			if first, ok := seenKeys[row.ExternalKey]; ok {
+				report.Rejected = append(report.Rejected, ImportRowError{
+					Line:        row.Line,
+					ExternalKey: row.ExternalKey,
+					Message:     fmt.Sprintf("duplicate external_key, first used on line %d", first),
+				})
+				continue
+			}
+			seenKeys[row.ExternalKey] = row.Line
+		}
+
+		// Keep validating after a rejection in all-or-nothing mode, but stop copying rows
+		if mode == ImportAllOrNothing && len(report.Rejected) > 0 {
+			continue
+		}
+
+		var externalKey interface{}
+		if row.ExternalKey != "" {
+			externalKey = row.ExternalKey
+		}
+		if _, err := stmt.ExecContext(dbCtx, row.Line, externalKey, row.Name, row.Description, row.Price, row.StockQty); err != nil {
+			return nil, dbError(fmt.Sprintf("failed to copy row %d", row.Line), err)
+		}
+	}
+
+	// Flush the COPY
+	if _, err := stmt.ExecContext(dbCtx); err != nil {
+		return nil, dbError("failed to copy rows", err)
+	}
+
+	span.SetAttributes(
+		attribute.Int("import.rows", report.Rows),
+		attribute.Int("import.rejected", len(report.Rejected)),
+	)
+
+	if mode == ImportAllOrNothing && len(report.Rejected) > 0 {
+		span.SetAttributes(attribute.String("db.result", "rejected"))
+		return report, nil
+	}
+
+	// Lock the products being updated first, so the old stock levels read below stay accurate
+	if _, err := tx.ExecContext(dbCtx, `
+		SELECT p.id FROM products p
+		JOIN product_import i ON i.external_key = p.external_key
+		ORDER BY p.id
+		FOR UPDATE OF p`); err != nil {
+		return nil, dbError("failed to lock imported products", err)
+	}
+
+	// Stock held by active reservations cannot be taken away by an import
+	if err := rejectStockBelowReserved(dbCtx, tx, report); err != nil {
+		return nil, err
+	}
+	if mode == ImportAllOrNothing && len(report.Rejected) > 0 {
+		span.SetAttributes(
+			attribute.String("db.result", "rejected"),
+			attribute.Int("import.rejected", len(report.Rejected)),
+		)
+		return report, nil
+	}
+
+	// Update products whose external key already exists, unless the new stock is below the
+	// reserved quantity. The self-join keeps the old stock level, so stock changes still land
+	// in the ledger as corrections.
+	var updated int
+	err = tx.QueryRowContext(dbCtx, `
+		WITH updated AS (
+			UPDATE products p
+			SET name = i.name, description = i.description, price = i.price,
+				stock_quantity = i.stock_quantity, version = p.version + 1, updated_at = NOW()
+			FROM product_import i
+			JOIN products old ON old.external_key = i.external_key
+			WHERE p.id = old.id AND i.stock_quantity >= p.reserved_quantity
+			RETURNING p.id, p.stock_quantity, p.stock_quantity - old.stock_quantity AS delta
+		), movements AS (
+			INSERT INTO stock_movements (product_id, delta, reason, note, stock_after)
+			SELECT id, delta, $1, 'bulk import', stock_quantity FROM updated WHERE delta <> 0
+		)
+		SELECT COUNT(*) FROM updated`, StockCorrection).Scan(&updated)
+	if isStockViolation(err) {
+		return nil, ErrStockBelowReserved
+	}
+	if err != nil {
+		return nil, dbError("failed to update imported products", err)
+	}
+
+	insertResult, err := tx.ExecContext(dbCtx, `
+		INSERT INTO products (external_key, name, description, price, stock_quantity, created_at, updated_at)
+		SELECT i.external_key, i.name, i.description, i.price, i.stock_quantity, NOW(), NOW()
+		FROM product_import i
+		WHERE NOT EXISTS (SELECT 1 FROM products p WHERE p.external_key = i.external_key)
+		ORDER BY i.line`)
+	if err != nil {
+		return nil, dbError("failed to insert imported products", err)
+	}
+	inserted, err := insertResult.RowsAffected()
+	if err != nil {
+		return nil, dbError("failed to get rows affected", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
+			"component": "product",
+			"action":    "import",
+		}).Error("Error committing product import")
+		return nil, dbError("failed to commit import", err)
+	}
+
+	span.SetAttributes(attribute.Int("import.rejected", len(report.Rejected)))
+
+	report.Inserted = int(inserted)
+	report.Updated = updated
+	report.Committed = true
+
+	span.SetAttributes(
+		attribute.String("db.result", "imported"),
+		attribute.Int("import.inserted", report.Inserted),
+		attribute.Int("import.updated", report.Updated),
+	)
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"component": "product",
+		"action":    "import",
+		"mode":      mode,
+		"rows":      report.Rows,
+		"inserted":  report.Inserted,
+		"updated":   report.Updated,
+		"rejected":  len(report.Rejected),
+	}).Info("Imported products")
+
+	return report, nil
+}
+
+// rejectStockBelowReserved adds a rejected row for every row that would set a product's stock
+// below the quantity its active reservations hold. The products must already be locked.
+func rejectStockBelowReserved(ctx context.Context, tx *sql.Tx, report *ImportReport) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT i.line, i.external_key, i.stock_quantity, p.reserved_quantity
+		FROM product_import i
+		JOIN products p ON p.external_key = i.external_key
+		WHERE i.stock_quantity < p.reserved_quantity
+		ORDER BY i.line`)
+	if err != nil {
+		return dbError("failed to check imported stock", err)
+	}
+	defer rows.Close()
+
+	found := false
+	for rows.Next() {
+		var line, stock, reserved int
+		var externalKey string
+		if err := rows.Scan(&line, &externalKey, &stock, &reserved); err != nil {
+			return dbError("failed to scan imported stock", err)
+		}
+		found = true
+		message := fmt.Sprintf("must be at least %d, the quantity held by active reservations", reserved)
+		report.Rejected = append(report.Rejected, ImportRowError{
+			Line:        line,
+			ExternalKey: externalKey,
+			Message:     "stock_quantity " + message,
+			Fields:      []FieldError{{Field: "stock_quantity", Code: CodeBelowReserved, Message: message}},
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return dbError("failed to iterate imported stock", err)
+	}
+
+	if found {
+		sort.Slice(report.Rejected, func(i, j int) bool {
+			return report.Rejected[i].Line < report.Rejected[j].Line
+		})
+	}
+	return nil
+}
@@ -3,35 +3,43 @@ package models
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"catalog-service/internal/db"
 	"catalog-service/internal/logger"
 
-	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
-	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Product represents a product in the catalog
 type Product struct {
-	ID          int     `json:"id" db:"id"`
-	Name        string  `json:"name" db:"name"`
-	Description string  `json:"description" db:"description"`
-	Price       float64 `json:"price" db:"price"`
-	StockQty    int     `json:"stock_quantity" db:"stock_quantity"`
+	ID          int       `json:"id" db:"id"`
+	ExternalKey *string   `json:"external_key" db:"external_key"`
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description" db:"description"`
+	Price       float64   `json:"price" db:"price"`
+	StockQty    int       `json:"stock_quantity" db:"stock_quantity"`
+	ReservedQty int       `json:"reserved_quantity" db:"reserved_quantity"`
+	Version     int       `json:"version" db:"version"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 }
 
-// ProductCreateRequest represents the request to create a new product
+// ProductCreateRequest represents the request to create a new product.
+// Validate holds its rules, so every transport checks it the same way.
 type ProductCreateRequest struct {
-	Name        string  `json:"name" binding:"required"`
+	Name        string  `json:"name"`
 	Description string  `json:"description"`
-	Price       float64 `json:"price" binding:"required,gt=0"`
-	StockQty    int     `json:"stock_quantity" binding:"gte=0"`
+	Price       float64 `json:"price"`
+	StockQty    int     `json:"stock_quantity"`
 }
 
-// ProductUpdateRequest represents the request to update a product
+// ProductUpdateRequest represents the request to update a product; nil fields are left unchanged
 type ProductUpdateRequest struct {
 	Name        *string  `json:"name,omitempty"`
 	Description *string  `json:"description,omitempty"`
@@ -39,13 +47,51 @@ type ProductUpdateRequest struct {
 	StockQty    *int     `json:"stock_quantity,omitempty"`
 }
 
+// ProductBatchGetRequest represents the request to look up several products at once.
+// The max must stay in line with MaxBatchGetProducts.
+type ProductBatchGetRequest struct {
+	IDs []int `json:"ids" binding:"required,min=1,max=100,dive,gt=0"`
+}
+
 // ProductResponse represents the response when returning a product
 type ProductResponse struct {
-	ID          int     `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	StockQty    int     `json:"stock_quantity"`
+	ID           int       `json:"id"`
+	ExternalKey  *string   `json:"external_key"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	Price        float64   `json:"price"`
+	StockQty     int       `json:"stock_quantity"`
+	ReservedQty  int       `json:"reserved_quantity"`
+	AvailableQty int       `json:"available_quantity"`
+	Version      int       `json:"version"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// productColumns is the column list every product query selects, in scanProduct order
+const productColumns = `id, external_key, name, description, price, stock_quantity, reserved_quantity, version, created_at, updated_at`
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanProduct scans a row selected with productColumns into a Product.
+// Any extra destinations are scanned from the columns that follow productColumns.
+func scanProduct(row rowScanner, product *Product, extra ...interface{}) error {
+	dest := []interface{}{
+		&product.ID,
+		&product.ExternalKey,
+		&product.Name,
+		&product.Description,
+		&product.Price,
+		&product.StockQty,
+		&product.ReservedQty,
+		&product.Version,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+	}
+	return row.Scan(append(dest, extra...)...)
 }
 
 // ProductService handles database operations for products
@@ -58,50 +104,49 @@ func NewProductService(db *sql.DB) *ProductService {
 	return &ProductService{db: db}
 }
 
+// PoolStats returns the connection pool statistics of the service's database
+func (s *ProductService) PoolStats() sql.DBStats {
+	return s.db.Stats()
+}
+
 // CreateProduct creates a new product in the database
-func (s *ProductService) CreateProduct(ctx *gin.Context, req ProductCreateRequest) (*Product, error) {
-	// Start a database span
-	tracer := otel.Tracer("catalog-service")
-	dbCtx, span := tracer.Start(ctx.Request.Context(), "db.create_product")
-	defer span.End()
+func (s *ProductService) CreateProduct(ctx context.Context, req ProductCreateRequest) (*Product, error) {
+	dbCtx := db.WithQuery(ctx, "create_product", "products")
+	span := trace.SpanFromContext(ctx)
+
+	if err := req.Validate(); err != nil {
+		span.SetAttributes(attribute.String("db.result", "invalid"))
+		return nil, err
+	}
 
 	// Add span attributes
 	span.SetAttributes(
-		attribute.String("db.operation", "INSERT"),
-		attribute.String("db.table", "products"),
 		attribute.String("product.name", req.Name),
 		attribute.Float64("product.price", req.Price),
 	)
 
 	query := `
-		INSERT INTO products (name, description, price, stock_quantity)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, name, description, price, stock_quantity`
+		INSERT INTO products (name, description, price, stock_quantity, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		RETURNING ` + productColumns
 
 	var product Product
-	err := s.db.QueryRowContext(dbCtx, query, req.Name, req.Description, req.Price, req.StockQty).Scan(
-		&product.ID,
-		&product.Name,
-		&product.Description,
-		&product.Price,
-		&product.StockQty,
-	)
+	err := scanProduct(s.db.QueryRowContext(dbCtx, query, req.Name, req.Description, req.Price, req.StockQty), &product)
 	if err != nil {
-		span.RecordError(err)
-		logger.WithError(err).WithFields(logrus.Fields{
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
 			"component": "product",
 			"action":    "create",
 			"name":      req.Name,
 			"price":     req.Price,
 		}).Error("Error creating product")
-		return nil, fmt.Errorf("failed to create product: %v", err)
+		return nil, dbError("failed to create product", err)
 	}
 
 	span.SetAttributes(
 		attribute.Int("product.id", product.ID),
 	)
 
-	logger.WithFields(logrus.Fields{
+	logger.FromContext(ctx).WithFields(logrus.Fields{
 		"component":  "product",
 		"action":     "create",
 		"product_id": product.ID,
@@ -113,41 +158,30 @@ func (s *ProductService) CreateProduct(ctx *gin.Context, req ProductCreateReques
 }
 
 // GetProduct retrieves a product by ID
-func (s *ProductService) GetProduct(ctx *gin.Context, id int) (*Product, error) {
-	// Start a database span
-	tracer := otel.Tracer("catalog-service")
-	dbCtx, span := tracer.Start(ctx.Request.Context(), "db.get_product")
-	defer span.End()
+func (s *ProductService) GetProduct(ctx context.Context, id int) (*Product, error) {
+	dbCtx := db.WithQuery(ctx, "get_product", "products")
+	span := trace.SpanFromContext(ctx)
 
 	// Add span attributes
 	span.SetAttributes(
-		attribute.String("db.operation", "SELECT"),
-		attribute.String("db.table", "products"),
 		attribute.Int("product.id", id),
 	)
 
-	query := `SELECT id, name, description, price, stock_quantity FROM products WHERE id = $1`
+	query := `SELECT ` + productColumns + ` FROM products WHERE id = $1`
 
 	var product Product
-	err := s.db.QueryRowContext(dbCtx, query, id).Scan(
-		&product.ID,
-		&product.Name,
-		&product.Description,
-		&product.Price,
-		&product.StockQty,
-	)
+	err := scanProduct(s.db.QueryRowContext(dbCtx, query, id), &product)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			span.SetAttributes(attribute.String("db.result", "not_found"))
-			return nil, fmt.Errorf("product not found")
+			return nil, ErrProductNotFound
 		}
-		span.RecordError(err)
-		logger.WithError(err).WithFields(logrus.Fields{
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
 			"component":  "product",
 			"action":     "get",
 			"product_id": id,
 		}).Error("Error getting product")
-		return nil, fmt.Errorf("failed to get product: %v", err)
+		return nil, dbError("failed to get product", err)
 	}
 
 	span.SetAttributes(
@@ -158,66 +192,144 @@ func (s *ProductService) GetProduct(ctx *gin.Context, id int) (*Product, error)
 	return &product, nil
 }
 
-// GetAllProducts retrieves all products with basic pagination
-func (s *ProductService) GetAllProducts(ctx *gin.Context, offset, limit int) ([]Product, error) {
-	// Start a database span
-	tracer := otel.Tracer("catalog-service")
-	dbCtx, span := tracer.Start(ctx.Request.Context(), "db.get_all_products")
-	defer span.End()
+// MaxBatchGetProducts is the most products a single batch lookup may ask for
+const MaxBatchGetProducts = 100
+
+// GetProductsByIDs retrieves several products in one query. Products come back in the order
+// of ids (duplicates collapsed), and ids that do not exist are returned separately.
+func (s *ProductService) GetProductsByIDs(ctx context.Context, ids []int) ([]Product, []int, error) {
+	dbCtx := db.WithQuery(ctx, "batch_get_products", "products")
+	span := trace.SpanFromContext(ctx)
+
+	// Collapse duplicates, keeping the first position of each id
+	unique := make([]int, 0, len(ids))
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
 
-	// Add span attributes
 	span.SetAttributes(
-		attribute.String("db.operation", "SELECT"),
-		attribute.String("db.table", "products"),
-		attribute.Int("query.offset", offset),
-		attribute.Int("query.limit", limit),
+		attribute.Int("products.requested", len(unique)),
+	)
+
+	query := `SELECT ` + productColumns + ` FROM products WHERE id = ANY($1)`
+
+	rows, err := s.db.QueryContext(dbCtx, query, pq.Array(unique))
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
+			"component": "product",
+			"action":    "batch_get",
+			"requested": len(unique),
+		}).Error("Error getting products by ID")
+		return nil, nil, dbError("failed to get products", err)
+	}
+	defer rows.Close()
+
+	found := make(map[int]Product, len(unique))
+	for rows.Next() {
+		var product Product
+		if err := scanProduct(rows, &product); err != nil {
+			return nil, nil, dbError("failed to scan product", err)
+		}
+		found[product.ID] = product
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, dbError("failed to iterate products", err)
+	}
+
+	products := make([]Product, 0, len(found))
+	notFound := []int{}
+	for _, id := range unique {
+		if product, ok := found[id]; ok {
+			products = append(products, product)
+		} else {
+			notFound = append(notFound, id)
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("products.found", len(products)),
+		attribute.Int("products.not_found", len(notFound)),
+	)
+
+	return products, notFound, nil
+}
+
+// GetAllProducts retrieves one page of products matching the filter in the requested order.
+// Pages are selected by offset, or by keyset when page.After is set.
+func (s *ProductService) GetAllProducts(ctx context.Context, filter ProductFilter, page ProductPage) ([]Product, error) {
+	dbCtx := db.WithQuery(ctx, "get_all_products", "products")
+	span := trace.SpanFromContext(ctx)
+
+	// Add span attributes, including the active filters so slow filter combinations show up in traces
+	span.SetAttributes(
+		attribute.Int("query.limit", page.Limit),
 	)
+	span.SetAttributes(filter.attributes()...)
+
+	// Build the query with positional placeholders only; the sort column comes from a whitelist
+	args := &queryArgs{}
+	conditions := filter.conditions(args)
+
+	var pageClause string
+	if page.After != nil {
+		// Keyset pagination: continue after the last row of the previous page
+		span.SetAttributes(attribute.String("query.pagination", "keyset"))
+		conditions = append(conditions, page.After.keysetCondition(filter.Sort, args))
+		pageClause = ` LIMIT ` + args.add(page.Limit)
+	} else {
+		span.SetAttributes(
+			attribute.String("query.pagination", "offset"),
+			attribute.Int("query.offset", page.Offset),
+		)
+		pageClause = ` LIMIT ` + args.add(page.Limit) + ` OFFSET ` + args.add(page.Offset)
+	}
+
+	var where string
+	if len(conditions) > 0 {
+		where = ` WHERE ` + strings.Join(conditions, " AND ")
+	}
 
-	query := `SELECT id, name, description, price, stock_quantity FROM products ORDER BY id LIMIT $1 OFFSET $2`
+	query := `SELECT ` + productColumns + ` FROM products` + where +
+		` ORDER BY ` + filter.Sort.orderBy() + pageClause
 
-	rows, err := s.db.QueryContext(dbCtx, query, limit, offset)
+	rows, err := s.db.QueryContext(dbCtx, query, args.values...)
 	if err != nil {
-		span.RecordError(err)
-		logger.WithError(err).WithFields(logrus.Fields{
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
 			"component": "product",
 			"action":    "list",
-			"offset":    offset,
-			"limit":     limit,
+			"offset":    page.Offset,
+			"limit":     page.Limit,
 		}).Error("Error getting products")
-		return nil, fmt.Errorf("failed to get products: %v", err)
+		return nil, dbError("failed to get products", err)
 	}
 	defer rows.Close()
 
 	var products []Product
 	for rows.Next() {
 		var product Product
-		err := rows.Scan(
-			&product.ID,
-			&product.Name,
-			&product.Description,
-			&product.Price,
-			&product.StockQty,
-		)
+		err := scanProduct(rows, &product)
 		if err != nil {
-			span.RecordError(err)
-			logger.WithError(err).WithFields(logrus.Fields{
+			logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
 				"component": "product",
 				"action":    "list",
 				"operation": "scan",
 			}).Error("Error scanning product row")
-			return nil, fmt.Errorf("failed to scan product: %v", err)
+			return nil, dbError("failed to scan product", err)
 		}
 		products = append(products, product)
 	}
 
 	if err = rows.Err(); err != nil {
-		span.RecordError(err)
-		logger.WithError(err).WithFields(logrus.Fields{
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
 			"component": "product",
 			"action":    "list",
 			"operation": "iterate",
 		}).Error("Error iterating products")
-		return nil, fmt.Errorf("failed to iterate products: %v", err)
+		return nil, dbError("failed to iterate products", err)
 	}
 
 	span.SetAttributes(
@@ -227,15 +339,57 @@ func (s *ProductService) GetAllProducts(ctx *gin.Context, offset, limit int) ([]
 	return products, nil
 }
 
-// UpdateProduct updates an existing product
-func (s *ProductService) UpdateProduct(ctx *gin.Context, id int, req ProductUpdateRequest) (*Product, error) {
-	// First, get the current product
-	current, err := s.GetProduct(ctx, id)
-	if err != nil {
+// UpdateProduct updates an existing product. The read, the merge and the write run in one
+// transaction with the row locked, so concurrent updates cannot overwrite each other.
+// If ifMatch is not empty, the update only happens when the current version is one of those
+// versions; otherwise the product is left unchanged and a "version mismatch" error is returned.
+func (s *ProductService) UpdateProduct(ctx context.Context, id int, req ProductUpdateRequest, ifMatch []int) (*Product, error) {
+	dbCtx := db.WithQuery(ctx, "update_product", "products")
+	span := trace.SpanFromContext(ctx)
+
+	// Add span attributes
+	span.SetAttributes(
+		attribute.Int("product.id", id),
+		attribute.Bool("product.conditional", len(ifMatch) > 0),
+	)
+
+	if err := req.Validate(); err != nil {
+		span.SetAttributes(attribute.String("db.result", "invalid"))
 		return nil, err
 	}
 
+	tx, err := s.db.BeginTx(dbCtx, nil)
+	if err != nil {
+		return nil, dbError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	// First, get and lock the current product
+	var current Product
+	err = scanProduct(tx.QueryRowContext(dbCtx, `SELECT `+productColumns+` FROM products WHERE id = $1 FOR UPDATE`, id), &current)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			span.SetAttributes(attribute.String("db.result", "not_found"))
+			return nil, ErrProductNotFound
+		}
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
+			"component":  "product",
+			"action":     "update",
+			"product_id": id,
+		}).Error("Error getting product for update")
+		return nil, dbError("failed to get product", err)
+	}
+
+	span.SetAttributes(attribute.Int("product.version", current.Version))
+
+	// Check the precondition against the locked row
+	if len(ifMatch) > 0 && !slices.Contains(ifMatch, current.Version) {
+		span.SetAttributes(attribute.String("db.result", "version_mismatch"))
+		return &current, ErrVersionMismatch
+	}
+
 	// Update only the fields that were provided
+	previousStock := current.StockQty
 	if req.Name != nil {
 		current.Name = *req.Name
 	}
@@ -246,73 +400,74 @@ func (s *ProductService) UpdateProduct(ctx *gin.Context, id int, req ProductUpda
 		current.Price = *req.Price
 	}
 	if req.StockQty != nil {
+		// Stock held by active reservations cannot be taken away by an absolute update
+		if *req.StockQty < current.ReservedQty {
+			span.SetAttributes(attribute.String("db.result", "insufficient_stock"))
+			return nil, &InsufficientStockError{
+				ProductID: id,
+				Requested: previousStock - *req.StockQty,
+				Available: max(previousStock-current.ReservedQty, 0),
+				Field:     "stock_quantity",
+			}
+		}
 		current.StockQty = *req.StockQty
 	}
 
-	// Start a database span for the update
-	tracer := otel.Tracer("catalog-service")
-	dbCtx, span := tracer.Start(ctx.Request.Context(), "db.update_product")
-	defer span.End()
-
-	// Add span attributes
-	span.SetAttributes(
-		attribute.String("db.operation", "UPDATE"),
-		attribute.String("db.table", "products"),
-		attribute.Int("product.id", id),
-	)
-
 	// Update the database
 	query := `
-		UPDATE products 
-		SET name = $1, description = $2, price = $3, stock_quantity = $4
+		UPDATE products
+		SET name = $1, description = $2, price = $3, stock_quantity = $4,
+			version = version + 1, updated_at = NOW()
 		WHERE id = $5
-		RETURNING id, name, description, price, stock_quantity`
+		RETURNING ` + productColumns
 
 	var product Product
-	err = s.db.QueryRowContext(dbCtx, query, current.Name, current.Description, current.Price, current.StockQty, id).Scan(
-		&product.ID,
-		&product.Name,
-		&product.Description,
-		&product.Price,
-		&product.StockQty,
-	)
+	err = scanProduct(tx.QueryRowContext(dbCtx, query, current.Name, current.Description, current.Price, current.StockQty, id), &product)
+	if err == nil && product.StockQty != previousStock {
+		// An absolute stock change still goes into the ledger as a correction
+		_, err = insertStockMovement(dbCtx, tx, id, product.StockQty-previousStock, StockCorrection, "set via product update", product.StockQty)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if isStockViolation(err) {
+		span.SetAttributes(attribute.String("db.result", "insufficient_stock"))
+		return nil, ErrStockBelowReserved
+	}
 	if err != nil {
-		span.RecordError(err)
-		logger.WithError(err).WithFields(logrus.Fields{
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
 			"component":  "product",
 			"action":     "update",
 			"product_id": id,
 		}).Error("Error updating product")
-		return nil, fmt.Errorf("failed to update product: %v", err)
+		return nil, dbError("failed to update product", err)
 	}
 
 	span.SetAttributes(
 		attribute.String("product.name", product.Name),
 		attribute.Float64("product.price", product.Price),
+		attribute.Int("product.new_version", product.Version),
 	)
 
-	logger.WithFields(logrus.Fields{
+	logger.FromContext(ctx).WithFields(logrus.Fields{
 		"component":  "product",
 		"action":     "update",
 		"product_id": product.ID,
 		"name":       product.Name,
 		"price":      product.Price,
+		"version":    product.Version,
 	}).Info("Updated product")
 
 	return &product, nil
 }
 
 // DeleteProduct deletes a product by ID
-func (s *ProductService) DeleteProduct(ctx *gin.Context, id int) error {
-	// Start a database span
-	tracer := otel.Tracer("catalog-service")
-	dbCtx, span := tracer.Start(ctx.Request.Context(), "db.delete_product")
-	defer span.End()
+func (s *ProductService) DeleteProduct(ctx context.Context, id int) error {
+	dbCtx := db.WithQuery(ctx, "delete_product", "products")
+	span := trace.SpanFromContext(ctx)
 
 	// Add span attributes
 	span.SetAttributes(
-		attribute.String("db.operation", "DELETE"),
-		attribute.String("db.table", "products"),
 		attribute.Int("product.id", id),
 	)
 
@@ -320,30 +475,28 @@ func (s *ProductService) DeleteProduct(ctx *gin.Context, id int) error {
 
 	result, err := s.db.ExecContext(dbCtx, query, id)
 	if err != nil {
-		span.RecordError(err)
-		logger.WithError(err).WithFields(logrus.Fields{
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
 			"component":  "product",
 			"action":     "delete",
 			"product_id": id,
 		}).Error("Error deleting product")
-		return fmt.Errorf("failed to delete product: %v", err)
+		return dbError("failed to delete product", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		span.RecordError(err)
-		logger.WithError(err).WithFields(logrus.Fields{
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
 			"component":  "product",
 			"action":     "delete",
 			"product_id": id,
 			"operation":  "rows_affected",
 		}).Error("Error getting rows affected for product")
-		return fmt.Errorf("failed to get rows affected: %v", err)
+		return dbError("failed to get rows affected", err)
 	}
 
 	if rowsAffected == 0 {
 		span.SetAttributes(attribute.String("db.result", "not_found"))
-		return fmt.Errorf("product not found")
+		return ErrProductNotFound
 	}
 
 	span.SetAttributes(
@@ -351,7 +504,7 @@ func (s *ProductService) DeleteProduct(ctx *gin.Context, id int) error {
 		attribute.String("db.result", "deleted"),
 	)
 
-	logger.WithFields(logrus.Fields{
+	logger.FromContext(ctx).WithFields(logrus.Fields{
 		"component":  "product",
 		"action":     "delete",
 		"product_id": id,
@@ -363,38 +516,46 @@ func (s *ProductService) DeleteProduct(ctx *gin.Context, id int) error {
 // ToResponse converts a Product to a ProductResponse
 func (p *Product) ToResponse() ProductResponse {
 	return ProductResponse{
-		ID:          p.ID,
-		Name:        p.Name,
-		Description: p.Description,
-		Price:       p.Price,
-		StockQty:    p.StockQty,
+		ID:           p.ID,
+		ExternalKey:  p.ExternalKey,
+		Name:         p.Name,
+		Description:  p.Description,
+		Price:        p.Price,
+		StockQty:     p.StockQty,
+		ReservedQty:  p.ReservedQty,
+		AvailableQty: p.AvailableQty(),
+		Version:      p.Version,
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+	}
+}
+
+// AvailableQty returns the stock that is not held by active reservations
+func (p *Product) AvailableQty() int {
+	if available := p.StockQty - p.ReservedQty; available > 0 {
+		return available
 	}
+	return 0
 }
 
-// GetProductCount returns the total number of products (helper for analysis)
-func (s *ProductService) GetProductCount(ctx context.Context) (int, error) {
-	tracer := otel.Tracer("catalog-service")
-	dbCtx, span := tracer.Start(ctx, "db.count_products")
-	defer span.End()
+// GetProductCount returns the number of products matching the filter
+func (s *ProductService) GetProductCount(ctx context.Context, filter ProductFilter) (int, error) {
+	dbCtx := db.WithQuery(ctx, "count_products", "products")
+	span := trace.SpanFromContext(ctx)
 
-	span.SetAttributes(
-		attribute.String("db.operation", "COUNT"),
-		attribute.String("db.table", "products"),
-	)
+	span.SetAttributes(filter.attributes()...)
 
-	// Simple fixed delay to demonstrate span duration
-	time.Sleep(80 * time.Millisecond)
+	args := &queryArgs{}
+	query := `SELECT COUNT(*) FROM products` + filter.where(args)
 
 	var count int
-	err := s.db.QueryRowContext(dbCtx, "SELECT COUNT(*) FROM products").Scan(&count)
+	err := s.db.QueryRowContext(dbCtx, query, args.values...).Scan(&count)
 	if err != nil {
-		span.RecordError(err)
-		return 0, err
+		return 0, dbError("failed to count products", err)
 	}
 
 	span.SetAttributes(
 		attribute.Int("db.result_count", count),
-		attribute.Int64("db.duration_ms", 80),
 	)
 
 	return count, nil
@@ -402,42 +563,28 @@ func (s *ProductService) GetProductCount(ctx context.Context) (int, error) {
 
 // GetProductByID returns a product by ID (helper for analysis)
 func (s *ProductService) GetProductByID(ctx context.Context, id int) (*Product, error) {
-	tracer := otel.Tracer("catalog-service")
-	dbCtx, span := tracer.Start(ctx, "db.product_lookup")
-	defer span.End()
+	dbCtx := db.WithQuery(ctx, "product_lookup", "products")
+	span := trace.SpanFromContext(ctx)
 
 	span.SetAttributes(
-		attribute.String("db.operation", "SELECT"),
-		attribute.String("db.table", "products"),
 		attribute.Int("db.product_id", id),
 	)
 
-	// Slightly longer delay for specific lookup
-	time.Sleep(120 * time.Millisecond)
-
-	query := `SELECT id, name, description, price, stock_quantity FROM products WHERE id = $1`
+	query := `SELECT ` + productColumns + ` FROM products WHERE id = $1`
 	var product Product
 
-	err := s.db.QueryRowContext(dbCtx, query, id).Scan(
-		&product.ID,
-		&product.Name,
-		&product.Description,
-		&product.Price,
-		&product.StockQty,
-	)
+	err := scanProduct(s.db.QueryRowContext(dbCtx, query, id), &product)
 
 	if err == sql.ErrNoRows {
 		span.SetAttributes(attribute.String("db.result", "not_found"))
-		return nil, fmt.Errorf("product not found")
+		return nil, ErrProductNotFound
 	} else if err != nil {
-		span.RecordError(err)
-		return nil, err
+		return nil, dbError("failed to get product", err)
 	}
 
 	span.SetAttributes(
 		attribute.String("db.result", "found"),
 		attribute.String("db.product_name", product.Name),
-		attribute.Int64("db.duration_ms", 120),
 	)
 
 	return &product, nil
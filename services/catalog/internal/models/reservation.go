@@ -0,0 +1,503 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"catalog-service/internal/db"
+	"catalog-service/internal/logger"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Reservation TTL limits
+const (
+	DefaultReservationTTL = 15 * time.Minute
+	MaxReservationTTL     = time.Hour
+)
+
+// Reservation statuses
+const (
+	ReservationActive    = "active"
+	ReservationCommitted = "committed"
+	ReservationReleased  = "released"
+	ReservationExpired   = "expired"
+)
+
+// Reservation holds stock for a checkout until it is committed, released or expires
+type Reservation struct {
+	ID        string            `json:"id"`
+	Status    string            `json:"status"`
+	Items     []ReservationItem `json:"items"`
+	ExpiresAt time.Time         `json:"expires_at"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// ReservationItem is the quantity of one product held by a reservation
+type ReservationItem struct {
+	ProductID int `json:"product_id" binding:"required,gt=0"`
+	Quantity  int `json:"quantity" binding:"required,gt=0"`
+}
+
+// ReservationCreateRequest represents the request to reserve stock
+type ReservationCreateRequest struct {
+	Items      []ReservationItem `json:"items" binding:"required,min=1,dive"`
+	TTLSeconds int               `json:"ttl_seconds,omitempty" binding:"gte=0"`
+}
+
+// InsufficientStockError reports a product that does not have enough available stock.
+// Field names the request field that asked for the stock, when there is one.
+type InsufficientStockError struct {
+	ProductID int
+	Requested int
+	Available int
+	Field     string
+}
+
+func (e *InsufficientStockError) Error() string {
+	return fmt.Sprintf("insufficient stock for product %d: requested %d, available %d",
+		e.ProductID, e.Requested, e.Available)
+}
+
+// Is makes an InsufficientStockError match ErrConflict
+func (e *InsufficientStockError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// ReservationService handles stock reservations
+type ReservationService struct {
+	db *sql.DB
+}
+
+// NewReservationService creates a new reservation service
+func NewReservationService(db *sql.DB) *ReservationService {
+	return &ReservationService{db: db}
+}
+
+// mergeReservationItems adds up duplicate products and sorts items by product ID.
+// Locking product rows in id order keeps concurrent reservations from deadlocking.
+func mergeReservationItems(items []ReservationItem) []ReservationItem {
+	quantities := make(map[int]int)
+	for _, item := range items {
+		quantities[item.ProductID] += item.Quantity
+	}
+
+	merged := make([]ReservationItem, 0, len(quantities))
+	for productID, quantity := range quantities {
+		merged = append(merged, ReservationItem{ProductID: productID, Quantity: quantity})
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].ProductID < merged[j].ProductID
+	})
+	return merged
+}
+
+// CreateReservation atomically holds stock for every item, or for none of them
+func (s *ReservationService) CreateReservation(ctx context.Context, req ReservationCreateRequest) (*Reservation, error) {
+	dbCtx := db.WithQuery(ctx, "create_reservation", "stock_reservations")
+	span := trace.SpanFromContext(ctx)
+
+	ttl := DefaultReservationTTL
+	if req.TTLSeconds > 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+	if ttl > MaxReservationTTL {
+		ttl = MaxReservationTTL
+	}
+
+	items := mergeReservationItems(req.Items)
+	productIDs := make([]int, len(items))
+	for i, item := range items {
+		productIDs[i] = item.ProductID
+	}
+
+	span.SetAttributes(
+		attribute.Int("reservation.items", len(items)),
+		attribute.Int64("reservation.ttl_seconds", int64(ttl.Seconds())),
+	)
+
+	tx, err := s.db.BeginTx(dbCtx, nil)
+	if err != nil {
+		return nil, dbError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the product rows so no other reservation can take the same stock meanwhile
+	rows, err := tx.QueryContext(dbCtx, `
+		SELECT id, stock_quantity, reserved_quantity
+		FROM products
+		WHERE id = ANY($1)
+		ORDER BY id
+		FOR UPDATE`, pq.Array(productIDs))
+	if err != nil {
+		return nil, dbError("failed to lock products", err)
+	}
+
+	available := make(map[int]int)
+	for rows.Next() {
+		var id, stock, reserved int
+		if err := rows.Scan(&id, &stock, &reserved); err != nil {
+			rows.Close()
+			return nil, dbError("failed to scan product stock", err)
+		}
+		available[id] = stock - reserved
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, dbError("failed to iterate product stock", err)
+	}
+
+	for _, item := range items {
+		have, ok := available[item.ProductID]
+		if !ok {
+			span.SetAttributes(attribute.String("db.result", "product_not_found"))
+			return nil, ErrProductNotFound
+		}
+		if have < item.Quantity {
+			span.SetAttributes(attribute.String("db.result", "insufficient_stock"))
+			return nil, &InsufficientStockError{ProductID: item.ProductID, Requested: item.Quantity, Available: max(have, 0)}
+		}
+	}
+
+	reservation := Reservation{Items: items}
+	err = tx.QueryRowContext(dbCtx, `
+		INSERT INTO stock_reservations (status, expires_at)
+		VALUES ($1, NOW() + $2 * INTERVAL '1 second')
+		RETURNING id, status, expires_at, created_at, updated_at`,
+		ReservationActive, int64(ttl.Seconds()),
+	).Scan(&reservation.ID, &reservation.Status, &reservation.ExpiresAt, &reservation.CreatedAt, &reservation.UpdatedAt)
+	if err != nil {
+		return nil, dbError("failed to create reservation", err)
+	}
+
+	for _, item := range items {
+		if _, err := tx.ExecContext(dbCtx, `
+			INSERT INTO stock_reservation_items (reservation_id, product_id, quantity)
+			VALUES ($1, $2, $3)`, reservation.ID, item.ProductID, item.Quantity); err != nil {
+			return nil, dbError("failed to create reservation item", err)
+		}
+		if _, err := tx.ExecContext(dbCtx, `
+			UPDATE products SET reserved_quantity = reserved_quantity + $1, version = version + 1, updated_at = NOW() WHERE id = $2`,
+			item.Quantity, item.ProductID); err != nil {
+			return nil, dbError("failed to reserve stock", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
+			"component": "reservation",
+			"action":    "create",
+		}).Error("Error committing reservation")
+		return nil, dbError("failed to commit reservation", err)
+	}
+
+	span.SetAttributes(attribute.String("reservation.id", reservation.ID))
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"component":      "reservation",
+		"action":         "create",
+		"reservation_id": reservation.ID,
+		"items":          len(items),
+		"expires_at":     reservation.ExpiresAt,
+	}).Info("Created stock reservation")
+
+	return &reservation, nil
+}
+
+// GetReservation retrieves a reservation and its items
+func (s *ReservationService) GetReservation(ctx context.Context, id string) (*Reservation, error) {
+	dbCtx := db.WithQuery(ctx, "get_reservation", "stock_reservations")
+	span := trace.SpanFromContext(ctx)
+
+	span.SetAttributes(
+		attribute.String("reservation.id", id),
+	)
+
+	var reservation Reservation
+	err := s.db.QueryRowContext(dbCtx, `
+		SELECT id, status, expires_at, created_at, updated_at
+		FROM stock_reservations WHERE id = $1`, id,
+	).Scan(&reservation.ID, &reservation.Status, &reservation.ExpiresAt, &reservation.CreatedAt, &reservation.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			span.SetAttributes(attribute.String("db.result", "not_found"))
+			return nil, ErrReservationNotFound
+		}
+		return nil, dbError("failed to get reservation", err)
+	}
+
+	rows, err := s.db.QueryContext(dbCtx, `
+		SELECT product_id, quantity FROM stock_reservation_items
+		WHERE reservation_id = $1 ORDER BY product_id`, id)
+	if err != nil {
+		return nil, dbError("failed to get reservation items", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item ReservationItem
+		if err := rows.Scan(&item.ProductID, &item.Quantity); err != nil {
+			return nil, dbError("failed to scan reservation item", err)
+		}
+		reservation.Items = append(reservation.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError("failed to iterate reservation items", err)
+	}
+
+	span.SetAttributes(attribute.String("reservation.status", reservation.Status))
+
+	return &reservation, nil
+}
+
+// lockActiveReservation locks a reservation row and checks that it can still change state.
+// A reservation past its expiry is released as expired and reported as such.
+func lockActiveReservation(ctx context.Context, tx *sql.Tx, id string) error {
+	var status string
+	var expired bool
+	err := tx.QueryRowContext(ctx, `
+		SELECT status, expires_at <= NOW() FROM stock_reservations
+		WHERE id = $1 FOR UPDATE`, id).Scan(&status, &expired)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrReservationNotFound
+		}
+		return dbError("failed to lock reservation", err)
+	}
+
+	if status != ReservationActive {
+		return ErrReservationNotActive
+	}
+	if expired {
+		return ErrReservationExpired
+	}
+	return nil
+}
+
+// releaseStock gives the held stock of the reservations back and marks them with the final status.
+// The products are locked in id order first, like CreateReservation and AdjustStock lock them,
+// so releasing cannot deadlock with a reservation or adjustment of the same products.
+func releaseStock(ctx context.Context, tx *sql.Tx, status string, ids ...string) error {
+	if _, err := tx.ExecContext(ctx, `
+		SELECT id FROM products
+		WHERE id IN (SELECT product_id FROM stock_reservation_items WHERE reservation_id = ANY($1))
+		ORDER BY id
+		FOR UPDATE`, pq.Array(ids)); err != nil {
+		return dbError("failed to lock reserved products", err)
+	}
+
+	// Several reservations of a batch can hold the same product, so their quantities are summed
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE products p
+		SET reserved_quantity = p.reserved_quantity - i.quantity, version = p.version + 1, updated_at = NOW()
+		FROM (
+			SELECT product_id, SUM(quantity) AS quantity
+			FROM stock_reservation_items
+			WHERE reservation_id = ANY($1)
+			GROUP BY product_id
+		) i
+		WHERE p.id = i.product_id`, pq.Array(ids)); err != nil {
+		return dbError("failed to release reserved stock", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE stock_reservations SET status = $1, updated_at = NOW() WHERE id = ANY($2)`, status, pq.Array(ids)); err != nil {
+		return dbError("failed to update reservation status", err)
+	}
+	return nil
+}
+
+// lockReservedStock locks the products of a reservation in id order and checks that selling the
+// held quantities leaves every product with enough stock for the other reservations' holds
+func lockReservedStock(ctx context.Context, tx *sql.Tx, id string) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT p.id, i.quantity, p.stock_quantity - p.reserved_quantity + i.quantity
+		FROM stock_reservation_items i
+		JOIN products p ON p.id = i.product_id
+		WHERE i.reservation_id = $1
+		ORDER BY p.id
+		FOR UPDATE OF p`, id)
+	if err != nil {
+		return dbError("failed to lock reserved products", err)
+	}
+	defer rows.Close()
+
+	var shortage *InsufficientStockError
+	for rows.Next() {
+		var productID, quantity, available int
+		if err := rows.Scan(&productID, &quantity, &available); err != nil {
+			return dbError("failed to scan reserved stock", err)
+		}
+		if available < quantity && shortage == nil {
+			shortage = &InsufficientStockError{ProductID: productID, Requested: quantity, Available: max(available, 0)}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return dbError("failed to iterate reserved stock", err)
+	}
+	if shortage != nil {
+		return shortage
+	}
+	return nil
+}
+
+// CommitReservation turns the held stock into a sale: stock on hand and reserved
+// stock both go down by the reserved quantity
+func (s *ReservationService) CommitReservation(ctx context.Context, id string) (*Reservation, error) {
+	return s.finishReservation(ctx, id, ReservationCommitted)
+}
+
+// ReleaseReservation gives the held stock back without selling it
+func (s *ReservationService) ReleaseReservation(ctx context.Context, id string) (*Reservation, error) {
+	return s.finishReservation(ctx, id, ReservationReleased)
+}
+
+// finishReservation moves an active reservation to its final status in one transaction
+func (s *ReservationService) finishReservation(ctx context.Context, id, status string) (*Reservation, error) {
+	// Start a database span
+	queryName := "commit_reservation"
+	if status == ReservationReleased {
+		queryName = "release_reservation"
+	}
+	dbCtx := db.WithQuery(ctx, queryName, "stock_reservations")
+	span := trace.SpanFromContext(ctx)
+
+	span.SetAttributes(
+		attribute.String("reservation.id", id),
+	)
+
+	tx, err := s.db.BeginTx(dbCtx, nil)
+	if err != nil {
+		return nil, dbError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockActiveReservation(dbCtx, tx, id); err != nil {
+		span.SetAttributes(attribute.String("db.result", err.Error()))
+		if errors.Is(err, ErrReservationExpired) {
+			// Release the hold right away instead of waiting for the reaper. If that fails the
+			// stock is still held, so report the failure rather than the expiry.
+			if releaseErr := releaseStock(dbCtx, tx, ReservationExpired, id); releaseErr != nil {
+				return nil, releaseErr
+			}
+			if commitErr := tx.Commit(); commitErr != nil {
+				return nil, dbError("failed to release expired reservation", commitErr)
+			}
+		}
+		return nil, err
+	}
+
+	if status == ReservationCommitted {
+		// The stock CHECK constraints already keep stock at or above the reserved quantity;
+		// checking under the lock first reports which product is short instead of a violation
+		if err := lockReservedStock(dbCtx, tx, id); err != nil {
+			span.SetAttributes(attribute.String("db.result", "insufficient_stock"))
+			return nil, err
+		}
+		if _, err := tx.ExecContext(dbCtx, `
+			UPDATE products p
+			SET stock_quantity = p.stock_quantity - i.quantity,
+				reserved_quantity = p.reserved_quantity - i.quantity,
+				version = p.version + 1,
+				updated_at = NOW()
+			FROM stock_reservation_items i
+			WHERE i.reservation_id = $1 AND p.id = i.product_id`, id); err != nil {
+			if isStockViolation(err) {
+				span.SetAttributes(attribute.String("db.result", "insufficient_stock"))
+				return nil, ErrStockBelowReserved
+			}
+			return nil, dbError("failed to commit reserved stock", err)
+		}
+		if _, err := tx.ExecContext(dbCtx, `
+			INSERT INTO stock_movements (product_id, delta, reason, note, stock_after)
+			SELECT i.product_id, -i.quantity, $2, 'reservation ' || i.reservation_id::text, p.stock_quantity
+			FROM stock_reservation_items i
+			JOIN products p ON p.id = i.product_id
+			WHERE i.reservation_id = $1`, id, StockSale); err != nil {
+			return nil, dbError("failed to record stock movements", err)
+		}
+		if _, err := tx.ExecContext(dbCtx, `
+			UPDATE stock_reservations SET status = $1, updated_at = NOW() WHERE id = $2`, status, id); err != nil {
+			return nil, dbError("failed to update reservation status", err)
+		}
+	} else if err := releaseStock(dbCtx, tx, status, id); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, dbError("failed to commit transaction", err)
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"component":      "reservation",
+		"action":         status,
+		"reservation_id": id,
+	}).Info("Finished stock reservation")
+
+	return s.GetReservation(ctx, id)
+}
+
+// ReleaseExpired releases up to limit active reservations whose TTL has passed.
+// SKIP LOCKED lets several replicas run the reaper at the same time without
+// blocking each other or releasing the same reservation twice.
+func (s *ReservationService) ReleaseExpired(ctx context.Context, limit int) (int, error) {
+	dbCtx := db.WithQuery(ctx, "release_expired_reservations", "stock_reservations")
+	span := trace.SpanFromContext(ctx)
+
+	span.SetAttributes(
+		attribute.Int("query.limit", limit),
+	)
+
+	tx, err := s.db.BeginTx(dbCtx, nil)
+	if err != nil {
+		return 0, dbError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(dbCtx, `
+		SELECT id FROM stock_reservations
+		WHERE status = $1 AND expires_at <= NOW()
+		ORDER BY expires_at
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED`, ReservationActive, limit)
+	if err != nil {
+		return 0, dbError("failed to find expired reservations", err)
+	}
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, dbError("failed to scan reservation id", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, dbError("failed to iterate expired reservations", err)
+	}
+
+	// Release the whole batch at once, so its products are locked in one ordered pass
+	if len(ids) > 0 {
+		if err := releaseStock(dbCtx, tx, ReservationExpired, ids...); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, dbError("failed to commit transaction", err)
+	}
+
+	span.SetAttributes(attribute.Int("reservations.released", len(ids)))
+
+	return len(ids), nil
+}
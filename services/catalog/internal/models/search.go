@@ -0,0 +1,149 @@
+package models
+
+import (
+	"context"
+	"html"
+	"strings"
+	"unicode"
+
+	"catalog-service/internal/db"
+	"catalog-service/internal/logger"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SearchHighlights holds matched fragments as HTML: the product text is escaped and the
+// matches are wrapped in <mark></mark> tags, which are the only markup it contains
+type SearchHighlights struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// ProductSearchResult is a product matched by a full-text search
+type ProductSearchResult struct {
+	Product    Product
+	Rank       float64
+	Highlights SearchHighlights
+}
+
+// ProductSearchResponse represents a search match in API responses
+type ProductSearchResponse struct {
+	ProductResponse
+	Rank       float64          `json:"rank"`
+	Highlights SearchHighlights `json:"highlights"`
+}
+
+// Postgres marks the matches with these control characters, which product text cannot contain,
+// so the text can be escaped before they are turned into <mark> tags
+const (
+	highlightStart = "\x01"
+	highlightStop  = "\x02"
+)
+
+// highlightReplacer turns the match markers into tags once the text has been escaped
+var highlightReplacer = strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>")
+
+// highlightHTML escapes a ts_headline fragment and wraps its matches in <mark> tags
+func highlightHTML(fragment string) string {
+	return highlightReplacer.Replace(html.EscapeString(fragment))
+}
+
+// buildPrefixTSQuery turns free text into a to_tsquery expression where every
+// word must match as a prefix, e.g. "mac pro" becomes "mac:* & pro:*".
+// Only letters and digits are kept, so user input can never inject tsquery operators.
+func buildPrefixTSQuery(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, strings.ToLower(word)+":*")
+	}
+	return strings.Join(terms, " & ")
+}
+
+// SearchProducts ranks products whose name or description match the search text
+func (s *ProductService) SearchProducts(ctx context.Context, text string, offset, limit int) ([]ProductSearchResult, error) {
+	dbCtx := db.WithQuery(ctx, "search_products", "products")
+	span := trace.SpanFromContext(ctx)
+
+	tsQuery := buildPrefixTSQuery(text)
+
+	// Add span attributes
+	span.SetAttributes(
+		attribute.String("search.query", text),
+		attribute.String("search.tsquery", tsQuery),
+		attribute.Int("query.offset", offset),
+		attribute.Int("query.limit", limit),
+	)
+
+	if tsQuery == "" {
+		span.SetAttributes(attribute.String("db.result", "invalid_query"))
+		return nil, ErrNoSearchTerms
+	}
+
+	query := `
+		WITH q AS (SELECT to_tsquery('english', $1) AS query)
+		SELECT ` + productColumns + `,
+			ts_rank(search_vector, q.query) AS rank,
+			ts_headline('english', name, q.query, $4::text || ', HighlightAll=true'),
+			ts_headline('english', coalesce(description, ''), q.query,
+				$4::text || ', MaxFragments=2, MaxWords=20, MinWords=5')
+		FROM products, q
+		WHERE search_vector @@ q.query
+		ORDER BY rank DESC, id
+		LIMIT $2 OFFSET $3`
+
+	selectors := "StartSel=" + highlightStart + ", StopSel=" + highlightStop
+	rows, err := s.db.QueryContext(dbCtx, query, tsQuery, limit, offset, selectors)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
+			"component": "product",
+			"action":    "search",
+			"query":     text,
+		}).Error("Error searching products")
+		return nil, dbError("failed to search products", err)
+	}
+	defer rows.Close()
+
+	var results []ProductSearchResult
+	for rows.Next() {
+		var result ProductSearchResult
+		err := scanProduct(rows, &result.Product,
+			&result.Rank,
+			&result.Highlights.Name,
+			&result.Highlights.Description,
+		)
+		if err != nil {
+			logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
+				"component": "product",
+				"action":    "search",
+				"operation": "scan",
+			}).Error("Error scanning search result row")
+			return nil, dbError("failed to scan search result", err)
+		}
+		result.Highlights.Name = highlightHTML(result.Highlights.Name)
+		result.Highlights.Description = highlightHTML(result.Highlights.Description)
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbError("failed to iterate search results", err)
+	}
+
+	span.SetAttributes(attribute.Int("products.count", len(results)))
+
+	return results, nil
+}
+
+// ToResponse converts a ProductSearchResult to a ProductSearchResponse
+func (r *ProductSearchResult) ToResponse() ProductSearchResponse {
+	return ProductSearchResponse{
+		ProductResponse: r.Product.ToResponse(),
+		Rank:            r.Rank,
+		Highlights:      r.Highlights,
+	}
+}
@@ -0,0 +1,50 @@
+package models
+
+import "testing"
+
+func TestHighlightHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		fragment string
+		want     string
+	}{
+		{name: "plain", fragment: "MacBook Pro", want: "MacBook Pro"},
+		{name: "match", fragment: "\x01MacBook\x02 Pro", want: "<mark>MacBook</mark> Pro"},
+		{
+			name:     "markup in the product text is escaped",
+			fragment: "<img src=x onerror=\"alert(1)\"> \x01Mac\x02",
+			want:     `&lt;img src=x onerror=&#34;alert(1)&#34;&gt; <mark>Mac</mark>`,
+		},
+		{name: "tags in the text are not matches", fragment: "<mark>Mac</mark>", want: "&lt;mark&gt;Mac&lt;/mark&gt;"},
+		{name: "entities", fragment: "Salt & \x01Pepper\x02 'mill'", want: "Salt &amp; <mark>Pepper</mark> &#39;mill&#39;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlightHTML(tt.fragment); got != tt.want {
+				t.Errorf("highlightHTML(%q) = %q, want %q", tt.fragment, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildPrefixTSQuery(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "mac pro", want: "mac:* & pro:*"},
+		{text: "  MacBook  ", want: "macbook:*"},
+		{text: "mac & !pro | (air)", want: "mac:* & pro:* & air:*"},
+		{text: "café 2024", want: "café:* & 2024:*"},
+		{text: "':* & |", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := buildPrefixTSQuery(tt.text); got != tt.want {
+				t.Errorf("buildPrefixTSQuery(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
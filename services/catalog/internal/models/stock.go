@@ -0,0 +1,205 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"catalog-service/internal/db"
+	"catalog-service/internal/logger"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Stock movement reasons
+const (
+	StockRestock    = "restock"
+	StockSale       = "sale"
+	StockDamage     = "damage"
+	StockCorrection = "correction"
+)
+
+// StockMovement is one entry in a product's stock ledger
+type StockMovement struct {
+	ID         int64     `json:"id"`
+	ProductID  int       `json:"product_id"`
+	Delta      int       `json:"delta"`
+	Reason     string    `json:"reason"`
+	Note       string    `json:"note"`
+	StockAfter int       `json:"stock_after"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// StockAdjustmentRequest represents a signed change to a product's stock on hand
+type StockAdjustmentRequest struct {
+	Delta  int    `json:"delta" binding:"required"`
+	Reason string `json:"reason" binding:"required,oneof=restock sale damage correction"`
+	Note   string `json:"note" binding:"max=500"`
+}
+
+// insertStockMovement appends a ledger row for a change that was just applied to the product
+func insertStockMovement(ctx context.Context, tx *sql.Tx, productID, delta int, reason, note string, stockAfter int) (*StockMovement, error) {
+	movement := StockMovement{
+		ProductID:  productID,
+		Delta:      delta,
+		Reason:     reason,
+		Note:       note,
+		StockAfter: stockAfter,
+	}
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO stock_movements (product_id, delta, reason, note, stock_after)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`,
+		productID, delta, reason, note, stockAfter,
+	).Scan(&movement.ID, &movement.CreatedAt)
+	if err != nil {
+		return nil, dbError("failed to record stock movement", err)
+	}
+	return &movement, nil
+}
+
+// AdjustStock adds a signed delta to a product's stock on hand and records it in the ledger.
+// The update is a single guarded statement, so concurrent adjustments never lose writes, and
+// stock can never drop below what active reservations hold (and therefore never below zero).
+func (s *ProductService) AdjustStock(ctx context.Context, id int, req StockAdjustmentRequest) (*Product, *StockMovement, error) {
+	dbCtx := db.WithQuery(ctx, "adjust_stock", "products")
+	span := trace.SpanFromContext(ctx)
+
+	span.SetAttributes(
+		attribute.Int("product.id", id),
+		attribute.Int("stock.delta", req.Delta),
+		attribute.String("stock.reason", req.Reason),
+	)
+
+	if err := req.Validate(); err != nil {
+		span.SetAttributes(attribute.String("db.result", "invalid"))
+		return nil, nil, err
+	}
+
+	tx, err := s.db.BeginTx(dbCtx, nil)
+	if err != nil {
+		return nil, nil, dbError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE products
+		SET stock_quantity = stock_quantity + $1, version = version + 1, updated_at = NOW()
+		WHERE id = $2 AND stock_quantity + $1 >= reserved_quantity
+		RETURNING ` + productColumns
+
+	var product Product
+	err = scanProduct(tx.QueryRowContext(dbCtx, query, req.Delta, id), &product)
+	if err == sql.ErrNoRows {
+		// Either the product does not exist or the guard rejected the change
+		var stock, reserved int
+		err = tx.QueryRowContext(dbCtx, `SELECT stock_quantity, reserved_quantity FROM products WHERE id = $1`, id).Scan(&stock, &reserved)
+		if err == sql.ErrNoRows {
+			span.SetAttributes(attribute.String("db.result", "not_found"))
+			return nil, nil, ErrProductNotFound
+		}
+		if err == nil {
+			span.SetAttributes(attribute.String("db.result", "insufficient_stock"))
+			return nil, nil, &InsufficientStockError{ProductID: id, Requested: -req.Delta, Available: max(stock-reserved, 0)}
+		}
+	}
+	if isOutOfRange(err) {
+		// A valid delta can still take the stock past what the column holds
+		span.SetAttributes(attribute.String("db.result", "invalid"))
+		return nil, nil, NewValidationError("delta", CodeOutOfRange, fmt.Sprintf("would take stock above %d", MaxStockQuantity))
+	}
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
+			"component":  "product",
+			"action":     "adjust_stock",
+			"product_id": id,
+		}).Error("Error adjusting stock")
+		return nil, nil, dbError("failed to adjust stock", err)
+	}
+
+	movement, err := insertStockMovement(dbCtx, tx, id, req.Delta, req.Reason, req.Note, product.StockQty)
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
+			"component":  "product",
+			"action":     "adjust_stock",
+			"product_id": id,
+		}).Error("Error recording stock movement")
+		return nil, nil, dbError("failed to adjust stock", err)
+	}
+
+	span.SetAttributes(
+		attribute.String("db.result", "adjusted"),
+		attribute.Int("stock.after", product.StockQty),
+		attribute.Int64("stock.movement_id", movement.ID),
+	)
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"component":   "product",
+		"action":      "adjust_stock",
+		"product_id":  id,
+		"delta":       req.Delta,
+		"reason":      req.Reason,
+		"stock_after": product.StockQty,
+	}).Info("Adjusted product stock")
+
+	return &product, movement, nil
+}
+
+// GetStockMovements returns a page of a product's stock ledger, newest first
+func (s *ProductService) GetStockMovements(ctx context.Context, id, offset, limit int) ([]StockMovement, error) {
+	dbCtx := db.WithQuery(ctx, "get_stock_movements", "stock_movements")
+	span := trace.SpanFromContext(ctx)
+
+	span.SetAttributes(
+		attribute.Int("product.id", id),
+		attribute.Int("query.offset", offset),
+		attribute.Int("query.limit", limit),
+	)
+
+	var exists bool
+	if err := s.db.QueryRowContext(dbCtx, `SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return nil, dbError("failed to get product", err)
+	}
+	if !exists {
+		span.SetAttributes(attribute.String("db.result", "not_found"))
+		return nil, ErrProductNotFound
+	}
+
+	rows, err := s.db.QueryContext(dbCtx, `
+		SELECT id, product_id, delta, reason, note, stock_after, created_at
+		FROM stock_movements
+		WHERE product_id = $1
+		ORDER BY id DESC
+		LIMIT $2 OFFSET $3`, id, limit, offset)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
+			"component":  "product",
+			"action":     "list_stock_movements",
+			"product_id": id,
+		}).Error("Error getting stock movements")
+		return nil, dbError("failed to get stock movements", err)
+	}
+	defer rows.Close()
+
+	movements := []StockMovement{}
+	for rows.Next() {
+		var m StockMovement
+		if err := rows.Scan(&m.ID, &m.ProductID, &m.Delta, &m.Reason, &m.Note, &m.StockAfter, &m.CreatedAt); err != nil {
+			return nil, dbError("failed to scan stock movement", err)
+		}
+		movements = append(movements, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError("failed to iterate stock movements", err)
+	}
+
+	span.SetAttributes(attribute.Int("stock.movements", len(movements)))
+
+	return movements, nil
+}
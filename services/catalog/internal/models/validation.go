@@ -0,0 +1,224 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Product field limits. MaxProductPrice and MaxStockQuantity are what the DECIMAL(10,2)
+// and INTEGER columns can hold, so a valid request never overflows in the database.
+const (
+	MaxProductNameLength        = 255
+	MaxProductDescriptionLength = 5000
+	MaxExternalKeyLength        = 100
+	MaxNameContainsLength       = 100
+	MaxProductPrice             = 99999999.99
+	MaxStockQuantity            = math.MaxInt32
+)
+
+// Field error codes, shared by every transport so clients can rely on them
+const (
+	CodeRequired          = "required"
+	CodeTooLong           = "too_long"
+	CodeOutOfRange        = "out_of_range"
+	CodePrecision         = "precision"
+	CodeInvalid           = "invalid"
+	CodeInvalidEncoding   = "invalid_encoding"
+	CodeInvalidCharacters = "invalid_characters"
+	CodeBelowReserved     = "below_reserved"
+)
+
+// fieldErrors collects the invalid fields of a request, so a client sees all of them at once
+type fieldErrors struct {
+	fields []FieldError
+}
+
+func (v *fieldErrors) add(field, code, message string) {
+	v.fields = append(v.fields, FieldError{Field: field, Code: code, Message: message})
+}
+
+// err returns a ValidationError with the collected fields, or nil if there are none
+func (v *fieldErrors) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: v.fields}
+}
+
+// Validate normalizes the request in place and checks it against the product rules
+func (r *ProductCreateRequest) Validate() error {
+	var v fieldErrors
+	r.validate(&v)
+	return v.err()
+}
+
+// validate adds the invalid fields to v, so an import row can check its embedded request
+func (r *ProductCreateRequest) validate(v *fieldErrors) {
+	r.Name = normalizeText(r.Name)
+	if r.Name == "" {
+		v.add("name", CodeRequired, "is required")
+	} else {
+		checkText(v, "name", r.Name, MaxProductNameLength, false)
+	}
+
+	r.Description = normalizeText(r.Description)
+	checkText(v, "description", r.Description, MaxProductDescriptionLength, true)
+
+	checkPrice(v, "price", r.Price)
+	checkStock(v, "stock_quantity", r.StockQty)
+}
+
+// Validate normalizes the fields that are set and checks them against the product rules.
+// A field that is sent must be valid on its own: an update cannot blank a product's name.
+func (r *ProductUpdateRequest) Validate() error {
+	var v fieldErrors
+
+	if r.Name != nil {
+		*r.Name = normalizeText(*r.Name)
+		if *r.Name == "" {
+			v.add("name", CodeRequired, "cannot be empty")
+		} else {
+			checkText(&v, "name", *r.Name, MaxProductNameLength, false)
+		}
+	}
+	if r.Description != nil {
+		*r.Description = normalizeText(*r.Description)
+		checkText(&v, "description", *r.Description, MaxProductDescriptionLength, true)
+	}
+	if r.Price != nil {
+		checkPrice(&v, "price", *r.Price)
+	}
+	if r.StockQty != nil {
+		checkStock(&v, "stock_quantity", *r.StockQty)
+	}
+
+	return v.err()
+}
+
+// Validate normalizes the row in place and checks it with the same rules as ProductCreateRequest
+func (r *ProductImportRow) Validate() error {
+	var v fieldErrors
+
+	r.ExternalKey = normalizeText(r.ExternalKey)
+	checkText(&v, "external_key", r.ExternalKey, MaxExternalKeyLength, false)
+	r.ProductCreateRequest.validate(&v)
+
+	return v.err()
+}
+
+// Validate checks that the delta fits the INTEGER stock column. The reason and note are
+// checked by the binding tags.
+func (r *StockAdjustmentRequest) Validate() error {
+	var v fieldErrors
+
+	if r.Delta < -MaxStockQuantity || r.Delta > MaxStockQuantity {
+		v.add("delta", CodeOutOfRange, fmt.Sprintf("must be between %d and %d", -MaxStockQuantity, MaxStockQuantity))
+	}
+
+	return v.err()
+}
+
+// Validate normalizes the filter in place and checks the price range and the name search
+func (f *ProductFilter) Validate() error {
+	var v fieldErrors
+
+	for _, bound := range []struct {
+		field string
+		value *float64
+	}{{"min_price", f.MinPrice}, {"max_price", f.MaxPrice}} {
+		if bound.value != nil && (math.IsNaN(*bound.value) || math.IsInf(*bound.value, 0) || *bound.value < 0) {
+			v.add(bound.field, CodeInvalid, "must be a non-negative number")
+		}
+	}
+	if len(v.fields) == 0 && f.MinPrice != nil && f.MaxPrice != nil && *f.MinPrice > *f.MaxPrice {
+		v.add("min_price", CodeOutOfRange, "cannot be greater than max_price")
+	}
+
+	f.NameContains = normalizeText(f.NameContains)
+	checkText(&v, "name_contains", f.NameContains, MaxNameContainsLength, false)
+
+	return v.err()
+}
+
+// normalizeText trims surrounding white space and composes the text into Unicode NFC,
+// so the same name typed on different systems is stored, searched and compared alike
+func normalizeText(s string) string {
+	s = strings.TrimSpace(s)
+	if !utf8.ValidString(s) {
+		// Left as is for checkText to reject; normalizing would replace the bad bytes
+		return s
+	}
+	return norm.NFC.String(s)
+}
+
+// checkText checks that a text field is valid UTF-8 of at most maxLen characters, without control
+// characters (other than line breaks and tabs in multiline fields) or bidirectional overrides,
+// which can make a name display differently from what it contains
+func checkText(v *fieldErrors, field, s string, maxLen int, multiline bool) {
+	if !utf8.ValidString(s) {
+		v.add(field, CodeInvalidEncoding, "must be valid UTF-8")
+		return
+	}
+	if utf8.RuneCountInString(s) > maxLen {
+		v.add(field, CodeTooLong, fmt.Sprintf("must be at most %d characters", maxLen))
+		return
+	}
+	for _, r := range s {
+		if unsafeRune(r, multiline) {
+			v.add(field, CodeInvalidCharacters, fmt.Sprintf("must not contain the character %U", r))
+			return
+		}
+	}
+}
+
+// unsafeRune reports whether a character is not allowed in product text
+func unsafeRune(r rune, multiline bool) bool {
+	switch {
+	case multiline && (r == '\n' || r == '\r' || r == '\t'):
+		return false
+	case unicode.IsControl(r):
+		// Includes NUL, which PostgreSQL rejects in text columns
+		return true
+	case r >= '\u202a' && r <= '\u202e', r >= '\u2066' && r <= '\u2069':
+		// Bidirectional embeddings, overrides and isolates
+		return true
+	}
+	return false
+}
+
+// checkPrice checks that a price is positive, fits DECIMAL(10,2) and has at most two decimals
+func checkPrice(v *fieldErrors, field string, price float64) {
+	switch {
+	case math.IsNaN(price) || math.IsInf(price, 0):
+		v.add(field, CodeInvalid, "must be a number")
+	case price <= 0:
+		v.add(field, CodeOutOfRange, "must be greater than 0")
+	case price > MaxProductPrice:
+		v.add(field, CodeOutOfRange, fmt.Sprintf("must be at most %.2f", MaxProductPrice))
+	case decimalPlaces(price) > 2:
+		v.add(field, CodePrecision, "must have at most 2 decimal places")
+	}
+}
+
+// checkStock checks that a stock quantity fits the INTEGER column and is not negative
+func checkStock(v *fieldErrors, field string, qty int) {
+	if qty < 0 || qty > MaxStockQuantity {
+		v.add(field, CodeOutOfRange, fmt.Sprintf("must be between 0 and %d", MaxStockQuantity))
+	}
+}
+
+// decimalPlaces counts the decimals of the shortest representation of f, which is what the
+// client sent: 19.99 has 2 even though the float64 closest to it has many more
+func decimalPlaces(f float64) int {
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if _, decimals, ok := strings.Cut(s, "."); ok {
+		return len(decimals)
+	}
+	return 0
+}
@@ -0,0 +1,225 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fieldCodes returns the field:code pairs of a validation error, in order
+func fieldCodes(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("error %v is not a *ValidationError", err)
+	}
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("error %v does not match ErrValidation", err)
+	}
+	codes := make([]string, len(validationErr.Fields))
+	for i, field := range validationErr.Fields {
+		codes[i] = field.Field + ":" + field.Code
+	}
+	return codes
+}
+
+func TestProductCreateRequestValidate(t *testing.T) {
+	valid := func() ProductCreateRequest {
+		return ProductCreateRequest{Name: "Widget", Description: "A widget", Price: 19.99, StockQty: 10}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(r *ProductCreateRequest)
+		want   []string
+	}{
+		{name: "valid", modify: func(r *ProductCreateRequest) {}},
+		{name: "no description", modify: func(r *ProductCreateRequest) { r.Description = "" }},
+		{name: "zero stock", modify: func(r *ProductCreateRequest) { r.StockQty = 0 }},
+		{name: "largest price", modify: func(r *ProductCreateRequest) { r.Price = MaxProductPrice }},
+		{name: "multiline description", modify: func(r *ProductCreateRequest) { r.Description = "Line one\n\tLine two\r\n" }},
+		{name: "name at the limit", modify: func(r *ProductCreateRequest) { r.Name = strings.Repeat("é", MaxProductNameLength) }},
+		{name: "missing name", modify: func(r *ProductCreateRequest) { r.Name = "" }, want: []string{"name:required"}},
+		{name: "blank name", modify: func(r *ProductCreateRequest) { r.Name = " \t " }, want: []string{"name:required"}},
+		{name: "long name", modify: func(r *ProductCreateRequest) { r.Name = strings.Repeat("a", MaxProductNameLength+1) }, want: []string{"name:too_long"}},
+		{name: "invalid UTF-8", modify: func(r *ProductCreateRequest) { r.Name = "Wid\xffget" }, want: []string{"name:invalid_encoding"}},
+		{name: "NUL in name", modify: func(r *ProductCreateRequest) { r.Name = "Wid\x00get" }, want: []string{"name:invalid_characters"}},
+		{name: "line break in name", modify: func(r *ProductCreateRequest) { r.Name = "Wid\nget" }, want: []string{"name:invalid_characters"}},
+		{name: "bidi override", modify: func(r *ProductCreateRequest) { r.Name = "Widget\u202etxt.exe" }, want: []string{"name:invalid_characters"}},
+		{name: "bidi isolate in description", modify: func(r *ProductCreateRequest) { r.Description = "\u2066x\u2069" }, want: []string{"description:invalid_characters"}},
+		{
+			name:   "long description",
+			modify: func(r *ProductCreateRequest) { r.Description = strings.Repeat("a", MaxProductDescriptionLength+1) },
+			want:   []string{"description:too_long"},
+		},
+		{name: "zero price", modify: func(r *ProductCreateRequest) { r.Price = 0 }, want: []string{"price:out_of_range"}},
+		{name: "negative price", modify: func(r *ProductCreateRequest) { r.Price = -1 }, want: []string{"price:out_of_range"}},
+		{name: "price too high", modify: func(r *ProductCreateRequest) { r.Price = MaxProductPrice + 0.01 }, want: []string{"price:out_of_range"}},
+		{name: "NaN price", modify: func(r *ProductCreateRequest) { r.Price = math.NaN() }, want: []string{"price:invalid"}},
+		{name: "infinite price", modify: func(r *ProductCreateRequest) { r.Price = math.Inf(1) }, want: []string{"price:invalid"}},
+		{name: "three decimals", modify: func(r *ProductCreateRequest) { r.Price = 19.999 }, want: []string{"price:precision"}},
+		{name: "negative stock", modify: func(r *ProductCreateRequest) { r.StockQty = -1 }, want: []string{"stock_quantity:out_of_range"}},
+		{name: "stock too high", modify: func(r *ProductCreateRequest) { r.StockQty = MaxStockQuantity + 1 }, want: []string{"stock_quantity:out_of_range"}},
+		{
+			name: "every field is reported",
+			modify: func(r *ProductCreateRequest) {
+				*r = ProductCreateRequest{Name: "", Description: "\x07", Price: 0.001, StockQty: -5}
+			},
+			want: []string{"name:required", "description:invalid_characters", "price:precision", "stock_quantity:out_of_range"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid()
+			tt.modify(&req)
+			if got := fieldCodes(t, req.Validate()); !slices.Equal(got, tt.want) {
+				t.Errorf("Validate() fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProductCreateRequestValidateNormalizes(t *testing.T) {
+	req := ProductCreateRequest{Name: "  Cafe\u0301 Mug \n", Description: " Decomposed e\u0301 ", Price: 5, StockQty: 1}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if req.Name != "Caf\u00e9 Mug" {
+		t.Errorf("name = %q, want it trimmed and in NFC", req.Name)
+	}
+	if req.Description != "Decomposed \u00e9" {
+		t.Errorf("description = %q, want it trimmed and in NFC", req.Description)
+	}
+}
+
+func TestProductUpdateRequestValidate(t *testing.T) {
+	str := func(s string) *string { return &s }
+	num := func(f float64) *float64 { return &f }
+	qty := func(n int) *int { return &n }
+
+	tests := []struct {
+		name string
+		req  ProductUpdateRequest
+		want []string
+	}{
+		{name: "nothing set", req: ProductUpdateRequest{}},
+		{name: "valid fields", req: ProductUpdateRequest{Name: str("Gadget"), Price: num(5.5), StockQty: qty(0)}},
+		{name: "description cleared", req: ProductUpdateRequest{Description: str("")}},
+		{name: "name blanked", req: ProductUpdateRequest{Name: str("   ")}, want: []string{"name:required"}},
+		{name: "long name", req: ProductUpdateRequest{Name: str(strings.Repeat("a", MaxProductNameLength+1))}, want: []string{"name:too_long"}},
+		{name: "zero price", req: ProductUpdateRequest{Price: num(0)}, want: []string{"price:out_of_range"}},
+		{name: "negative stock", req: ProductUpdateRequest{StockQty: qty(-1)}, want: []string{"stock_quantity:out_of_range"}},
+		{
+			name: "every field is reported",
+			req:  ProductUpdateRequest{Name: str(""), Description: str("\x00"), Price: num(1.234), StockQty: qty(-1)},
+			want: []string{"name:required", "description:invalid_characters", "price:precision", "stock_quantity:out_of_range"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fieldCodes(t, tt.req.Validate()); !slices.Equal(got, tt.want) {
+				t.Errorf("Validate() fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProductImportRowValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		row  ProductImportRow
+		want []string
+	}{
+		{
+			name: "valid",
+			row:  ProductImportRow{ExternalKey: "sku-1", ProductCreateRequest: ProductCreateRequest{Name: "Widget", Price: 1, StockQty: 1}},
+		},
+		{
+			name: "external key is optional",
+			row:  ProductImportRow{ProductCreateRequest: ProductCreateRequest{Name: "Widget", Price: 1}},
+		},
+		{
+			name: "long external key",
+			row: ProductImportRow{
+				ExternalKey:          strings.Repeat("k", MaxExternalKeyLength+1),
+				ProductCreateRequest: ProductCreateRequest{Name: "Widget", Price: 1},
+			},
+			want: []string{"external_key:too_long"},
+		},
+		{
+			name: "external key and product fields",
+			row:  ProductImportRow{ExternalKey: "sku\t1", ProductCreateRequest: ProductCreateRequest{Price: 1}},
+			want: []string{"external_key:invalid_characters", "name:required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fieldCodes(t, tt.row.Validate()); !slices.Equal(got, tt.want) {
+				t.Errorf("Validate() fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProductFilterValidate(t *testing.T) {
+	price := func(f float64) *float64 { return &f }
+
+	tests := []struct {
+		name   string
+		filter ProductFilter
+		want   []string
+	}{
+		{name: "empty", filter: ProductFilter{}},
+		{name: "price range", filter: ProductFilter{MinPrice: price(0), MaxPrice: price(10)}},
+		{name: "equal bounds", filter: ProductFilter{MinPrice: price(5), MaxPrice: price(5)}},
+		{name: "negative min", filter: ProductFilter{MinPrice: price(-1)}, want: []string{"min_price:invalid"}},
+		{name: "NaN max", filter: ProductFilter{MaxPrice: price(math.NaN())}, want: []string{"max_price:invalid"}},
+		{name: "inverted range", filter: ProductFilter{MinPrice: price(10), MaxPrice: price(5)}, want: []string{"min_price:out_of_range"}},
+		{
+			name:   "long search",
+			filter: ProductFilter{NameContains: strings.Repeat("a", MaxNameContainsLength+1)},
+			want:   []string{"name_contains:too_long"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fieldCodes(t, tt.filter.Validate()); !slices.Equal(got, tt.want) {
+				t.Errorf("Validate() fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStockAdjustmentRequestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		delta int
+		want  []string
+	}{
+		{name: "restock", delta: 10},
+		{name: "sale", delta: -3},
+		{name: "largest increase", delta: MaxStockQuantity},
+		{name: "largest decrease", delta: -MaxStockQuantity},
+		{name: "too large", delta: MaxStockQuantity + 1, want: []string{"delta:out_of_range"}},
+		{name: "too small", delta: -MaxStockQuantity - 1, want: []string{"delta:out_of_range"}},
+		{name: "largest int", delta: math.MaxInt, want: []string{"delta:out_of_range"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := StockAdjustmentRequest{Delta: tt.delta, Reason: StockRestock}
+			if got := fieldCodes(t, req.Validate()); !slices.Equal(got, tt.want) {
+				t.Errorf("Validate() fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Package requestid carries the X-Request-ID of a request through its context and on to
+// outgoing HTTP calls. Unlike the trace id it is always present, even for unsampled traces.
+package requestid
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// Header is the HTTP header, and lower-cased the gRPC metadata key, that carries the request id
+const Header = "X-Request-ID"
+
+// maxLength bounds a request id accepted from a client
+const maxLength = 128
+
+// contextKey is the context key of the request id
+type contextKey struct{}
+
+// New generates a request id
+func New() string {
+	return uuid.NewString()
+}
+
+// Valid reports whether a client-supplied request id can be used as is: 1 to 128 letters,
+// digits or - _ . : characters, so it is safe to echo in headers and write to logs
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-' || c == '_' || c == '.' || c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// NewContext returns a copy of ctx carrying the request id
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request id in ctx, or "" if there is none
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// Transport forwards the request id of the request context on outgoing HTTP calls
+type Transport struct {
+	// Base is the transport that sends the request; nil means http.DefaultTransport
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if id := FromContext(req.Context()); id != "" && req.Header.Get(Header) == "" {
+		// A RoundTripper must not modify the caller's request
+		req = req.Clone(req.Context())
+		req.Header.Set(Header, id)
+	}
+	return base.RoundTrip(req)
+}
@@ -1,20 +1,31 @@
 package server
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"net/http"
 	"strconv"
+	"sync/atomic"
 	"time"
 
+	"catalog-service/internal/auth"
+	"catalog-service/internal/config"
+	"catalog-service/internal/db"
 	"catalog-service/internal/handlers"
+	"catalog-service/internal/health"
 	"catalog-service/internal/logger"
 	"catalog-service/internal/metrics"
 	"catalog-service/internal/models"
+	"catalog-service/internal/requestid"
 	"catalog-service/internal/services"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -22,19 +33,35 @@ import (
 type Server struct {
 	router  *gin.Engine
 	db      *sql.DB
+	cfg     *config.Config
+	auth    auth.Authenticator
 	metrics *metrics.HTTPMetrics
+	reaper  *services.ReservationReaper
+	http    *http.Server
+
+	// shuttingDown fails the readiness probe while requests drain
+	shuttingDown atomic.Bool
+}
+
+// probePaths are the health probe endpoints, which are not logged
+var probePaths = map[string]bool{
+	"/health":   true,
+	"/livez":    true,
+	"/readyz":   true,
+	"/startupz": true,
 }
 
-// NewServer creates a new server instance
-func NewServer(database *sql.DB) *Server {
+// NewServer creates a new server instance. Write endpoints require the authenticator to accept
+// the request; a nil authenticator leaves them open.
+func NewServer(database *sql.DB, cfg *config.Config, authenticator auth.Authenticator) *Server {
 	// Set Gin to release mode for production
 	gin.SetMode(gin.ReleaseMode)
 
 	// Create router without default middleware (no default logging)
 	router := gin.New()
 
-	// Add recovery middleware (but not logging - we'll add our own)
-	router.Use(gin.Recovery())
+	// Add recovery middleware (but not logging - we'll add our own); panics become a 500 problem
+	router.Use(gin.CustomRecovery(handlers.Recovered))
 
 	// Initialize metrics
 	httpMetrics := metrics.NewHTTPMetrics()
@@ -42,40 +69,77 @@ func NewServer(database *sql.DB) *Server {
 	server := &Server{
 		router:  router,
 		db:      database,
+		cfg:     cfg,
+		auth:    authenticator,
 		metrics: httpMetrics,
 	}
+	server.http = &http.Server{
+		Handler:           router,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
 
 	// Add middleware in order:
 	// 1. OpenTelemetry tracing (creates spans)
 	router.Use(otelgin.Middleware("catalog-service"))
 
-	// 2. Our custom logging middleware (can use trace context)
+	// 2. Request id (needs the span, and the logger below needs the id)
+	server.router.Use(requestIDMiddleware())
+
+	// 3. Our custom logging middleware (can use trace context)
 	server.router.Use(server.loggingMiddleware())
 
-	// 3. Our metrics middleware
+	// 4. Our metrics middleware
 	server.router.Use(server.metricsMiddleware())
 
+	// 5. Error rendering, last so it runs right after the handler, before logging and
+	// metrics see the status code
+	server.router.Use(handlers.ErrorHandler())
+	server.router.NoRoute(handlers.NotFound)
+
 	// Setup routes
 	server.setupRoutes()
 
 	return server
 }
 
+// requestIDMiddleware accepts the client's X-Request-ID or generates one, echoes it on the
+// response, and records it in the request context, the logs and the request span
+func requestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+		c.Header(requestid.Header, id)
+
+		ctx := requestid.NewContext(c.Request.Context(), id)
+		ctx = logger.ContextWithFields(ctx, logrus.Fields{"request_id": id})
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("catalog.request_id", id))
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
 // loggingMiddleware logs HTTP requests with structured JSON and trace correlation
 func (s *Server) loggingMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 
+		// Every log line of the request carries its route
+		c.Request = c.Request.WithContext(logger.ContextWithFields(c.Request.Context(), logrus.Fields{
+			"http_method": c.Request.Method,
+			"http_route":  c.FullPath(),
+		}))
+
 		// Process request
 		c.Next()
 
 		// Skip logging for health check endpoints to reduce noise
-		if c.Request.URL.Path == "/health" {
+		if probePaths[c.Request.URL.Path] {
 			return
 		}
 
-		// Get trace information for correlation
-		spanCtx := trace.SpanContextFromContext(c.Request.Context())
 		fields := logrus.Fields{
 			"component":   "http",
 			"method":      c.Request.Method,
@@ -86,18 +150,12 @@ func (s *Server) loggingMiddleware() gin.HandlerFunc {
 			"user_agent":  c.Request.UserAgent(),
 		}
 
-		// Add trace correlation if available
-		if spanCtx.IsValid() {
-			fields["trace_id"] = spanCtx.TraceID().String()
-			fields["span_id"] = spanCtx.SpanID().String()
-		}
-
-		// Log request details
-		logger.WithFields(fields).Info("HTTP request processed")
+		// Log request details; FromContext adds the trace correlation
+		logger.FromContext(c.Request.Context()).WithFields(fields).Info("HTTP request processed")
 	}
 }
 
-// metricsMiddleware collects HTTP metrics for Prometheus
+// metricsMiddleware collects HTTP metrics for Prometheus and OTLP
 func (s *Server) metricsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Skip metrics collection for the metrics endpoint itself
@@ -107,8 +165,9 @@ func (s *Server) metricsMiddleware() gin.HandlerFunc {
 		}
 
 		// Record in-flight request
-		s.metrics.IncInFlight()
-		defer s.metrics.DecInFlight()
+		ctx := c.Request.Context()
+		s.metrics.IncInFlight(ctx)
+		defer s.metrics.DecInFlight(ctx)
 
 		// Record start time
 		start := time.Now()
@@ -121,6 +180,7 @@ func (s *Server) metricsMiddleware() gin.HandlerFunc {
 		statusCode := strconv.Itoa(c.Writer.Status())
 
 		s.metrics.RecordRequest(
+			ctx,
 			c.Request.Method,
 			c.FullPath(), // Use route pattern instead of actual path (e.g., "/api/v1/products/:id")
 			statusCode,
@@ -129,38 +189,129 @@ func (s *Server) metricsMiddleware() gin.HandlerFunc {
 	}
 }
 
+// healthChecks registers the dependency checks behind the probes.
+// Liveness has no dependency checks on purpose: a database outage must not restart the pod.
+func (s *Server) healthChecks() *health.Registry {
+	registry := health.NewRegistry()
+
+	registry.Register(health.Check{
+		Name:     "shutdown",
+		Run:      health.ShutdownCheck(&s.shuttingDown),
+		Timeout:  time.Second,
+		Critical: true,
+		Probes:   []health.Probe{health.Readiness},
+	})
+	registry.Register(health.Check{
+		Name:     "database",
+		Run:      health.DatabaseCheck(s.db),
+		Timeout:  2 * time.Second,
+		CacheTTL: 2 * time.Second,
+		Critical: true,
+		Probes:   []health.Probe{health.Readiness, health.Startup},
+	})
+	registry.Register(health.Check{
+		Name:     "migrations",
+		Run:      health.MigrationsCheck(&db.Database{DB: s.db}),
+		Timeout:  2 * time.Second,
+		CacheTTL: 30 * time.Second,
+		Critical: true,
+		Probes:   []health.Probe{health.Readiness, health.Startup},
+	})
+	// Losing traces is not a reason to stop serving traffic, so the exporter is only reported
+	registry.Register(health.Check{
+		Name:     "trace_exporter",
+		Run:      health.TCPCheck(s.cfg.Tracing.Endpoint),
+		Timeout:  time.Second,
+		CacheTTL: 30 * time.Second,
+		Probes:   []health.Probe{health.Readiness, health.Startup},
+	})
+
+	return registry
+}
+
 // setupRoutes configures all the routes for the server
 func (s *Server) setupRoutes() {
-	// Health check endpoint
-	healthHandler := handlers.NewHealthHandler(s.db)
-	s.router.GET("/health", healthHandler.HealthCheck)
+	// Health probe endpoints
+	healthHandler := handlers.NewHealthHandler(s.healthChecks())
+	s.router.GET("/livez", healthHandler.Livez)        // GET /livez
+	s.router.GET("/readyz", healthHandler.Readyz)      // GET /readyz
+	s.router.GET("/startupz", healthHandler.Startupz)  // GET /startupz
+	s.router.GET("/health", healthHandler.HealthCheck) // GET /health (same as /readyz)
+
+	// Metrics endpoint for Prometheus; exemplars are only sent in the OpenMetrics format
+	s.router.GET("/metrics", gin.WrapH(promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{
+		EnableOpenMetrics: true,
+	})))
+
+	// API reads are public; every route that changes data, and the admin routes, require authentication
+	requireAuth := handlers.RequireAuth(s.auth)
 
-	// Metrics endpoint for Prometheus
-	s.router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	// Effective configuration, secrets redacted
+	adminHandler := handlers.NewAdminHandler(s.cfg)
+	s.router.GET("/admin/config", requireAuth, adminHandler.GetConfig)
 
 	// Create product service and analysis service
 	productService := models.NewProductService(s.db)
 	analysisService := services.NewAnalysisService(productService)
 	productHandler := handlers.NewProductHandler(productService, analysisService)
 
+	// Create category service and handler
+	categoryService := models.NewCategoryService(s.db)
+	categoryHandler := handlers.NewCategoryHandler(categoryService, productService)
+
+	// Create reservation service, handler and the reaper that releases expired holds
+	reservationService := models.NewReservationService(s.db)
+	inventoryHandler := handlers.NewInventoryHandler(reservationService)
+	s.reaper = services.NewReservationReaper(reservationService, s.cfg.Reservations.ReaperInterval.Duration)
+
 	// Create frontend metrics handler
 	frontendMetricsHandler := handlers.NewFrontendMetricsHandler()
 
 	// API v1 routes
 	v1 := s.router.Group("/api/v1")
 	{
-		// Frontend metrics endpoint
+		// Frontend metrics endpoint; public, browsers report without credentials
 		v1.POST("/frontend-metrics", frontendMetricsHandler.HandleFrontendMetrics)
 
 		// Product routes
 		products := v1.Group("/products")
 		{
-			products.GET("", productHandler.GetProducts)            // GET /api/v1/products
-			products.POST("", productHandler.CreateProduct)         // POST /api/v1/products
-			products.GET("/analyze", productHandler.AnalyzeProduct) // GET /api/v1/products/analyze
-			products.GET("/:id", productHandler.GetProduct)         // GET /api/v1/products/:id
-			products.PUT("/:id", productHandler.UpdateProduct)      // PUT /api/v1/products/:id
-			products.DELETE("/:id", productHandler.DeleteProduct)   // DELETE /api/v1/products/:id
+			products.GET("", productHandler.GetProducts)                         // GET /api/v1/products
+			products.POST("", requireAuth, productHandler.CreateProduct)         // POST /api/v1/products
+			products.GET("/analyze", productHandler.AnalyzeProduct)              // GET /api/v1/products/analyze
+			products.GET("/search", productHandler.SearchProducts)               // GET /api/v1/products/search?q=
+			products.POST("/batch-get", productHandler.BatchGetProducts)         // POST /api/v1/products/batch-get (a read)
+			products.POST("/import", requireAuth, productHandler.ImportProducts) // POST /api/v1/products/import
+			products.GET("/export", productHandler.ExportProducts)               // GET /api/v1/products/export?format=
+			products.GET("/:id", productHandler.GetProduct)                      // GET /api/v1/products/:id
+			products.PUT("/:id", requireAuth, productHandler.UpdateProduct)      // PUT /api/v1/products/:id
+			products.DELETE("/:id", requireAuth, productHandler.DeleteProduct)   // DELETE /api/v1/products/:id
+
+			// Stock ledger routes
+			products.POST("/:id/stock/adjustments", requireAuth, productHandler.AdjustStock) // POST /api/v1/products/:id/stock/adjustments
+			products.GET("/:id/stock/adjustments", productHandler.GetStockMovements)         // GET /api/v1/products/:id/stock/adjustments
+		}
+
+		// Category routes
+		categories := v1.Group("/categories")
+		{
+			categories.GET("", categoryHandler.GetCategories)                                                  // GET /api/v1/categories
+			categories.POST("", requireAuth, categoryHandler.CreateCategory)                                   // POST /api/v1/categories
+			categories.GET("/:id", categoryHandler.GetCategory)                                                // GET /api/v1/categories/:id
+			categories.PUT("/:id", requireAuth, categoryHandler.UpdateCategory)                                // PUT /api/v1/categories/:id
+			categories.DELETE("/:id", requireAuth, categoryHandler.DeleteCategory)                             // DELETE /api/v1/categories/:id
+			categories.GET("/:id/products", categoryHandler.GetCategoryProducts)                               // GET /api/v1/categories/:id/products
+			categories.POST("/:id/products", requireAuth, categoryHandler.AddCategoryProducts)                 // POST /api/v1/categories/:id/products
+			categories.DELETE("/:id/products/:product_id", requireAuth, categoryHandler.RemoveCategoryProduct) // DELETE /api/v1/categories/:id/products/:product_id
+		}
+
+		// Inventory routes
+		inventory := v1.Group("/inventory")
+		{
+			inventory.POST("/reservations", requireAuth, inventoryHandler.CreateReservation)            // POST /api/v1/inventory/reservations
+			inventory.GET("/reservations/:id", inventoryHandler.GetReservation)                         // GET /api/v1/inventory/reservations/:id
+			inventory.POST("/reservations/:id/commit", requireAuth, inventoryHandler.CommitReservation) // POST /api/v1/inventory/reservations/:id/commit
+			inventory.DELETE("/reservations/:id", requireAuth, inventoryHandler.ReleaseReservation)     // DELETE /api/v1/inventory/reservations/:id
 		}
 	}
 
@@ -180,7 +331,7 @@ func (s *Server) setupRoutes() {
 	}
 }
 
-// Start starts the HTTP server
+// Start starts the HTTP server and blocks until Shutdown is called
 func (s *Server) Start(port string) error {
 	logger.WithFields(logrus.Fields{
 		"component": "server",
@@ -188,22 +339,48 @@ func (s *Server) Start(port string) error {
 		"port":      port,
 	}).Info("Starting server")
 
-	return s.router.Run(":" + port)
-}
+	s.http.Addr = ":" + port
 
-// Stop gracefully stops the server
-func (s *Server) Stop() error {
-	if s.db != nil {
-		logger.WithFields(logrus.Fields{
-			"component": "server",
-			"action":    "shutdown",
-		}).Info("Closing database connection")
+	// Start background jobs
+	s.reaper.Start()
 
-		return s.db.Close()
+	if err := s.http.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
 	}
 	return nil
 }
 
+// BeginShutdown makes the health check fail while the server keeps serving,
+// so load balancers stop routing new requests here before the listener closes
+func (s *Server) BeginShutdown() {
+	s.shuttingDown.Store(true)
+
+	logger.WithFields(logrus.Fields{
+		"component": "server",
+		"action":    "shutdown",
+	}).Info("Marked server as not ready")
+}
+
+// Shutdown stops accepting connections, waits for in-flight requests until ctx expires
+// and then stops background jobs. The database is left open for the caller to close.
+func (s *Server) Shutdown(ctx context.Context) error {
+	err := s.http.Shutdown(ctx)
+	if err != nil {
+		// Deadline passed, cut the remaining connections
+		s.http.Close()
+	}
+
+	// Stop background jobs before the database they use is closed
+	s.reaper.Stop()
+
+	logger.WithFields(logrus.Fields{
+		"component": "server",
+		"action":    "shutdown",
+	}).Info("HTTP server stopped")
+
+	return err
+}
+
 // GetDB returns the database connection (for testing purposes)
 func (s *Server) GetDB() *sql.DB {
 	return s.db
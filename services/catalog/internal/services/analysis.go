@@ -2,12 +2,14 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
 	"catalog-service/internal/logger"
 	"catalog-service/internal/models"
+	"catalog-service/internal/requestid"
 
 	"math/rand"
 
@@ -44,8 +46,14 @@ type DatabaseStats struct {
 	AverageLatencyMs   int64 `json:"average_latency_ms"`
 	SlowQueriesCount   int   `json:"slow_queries_count"`
 	ConnectionPoolUsed int   `json:"connection_pool_used"`
+	ConnectionPoolOpen int   `json:"connection_pool_open"`
+	ConnectionPoolIdle int   `json:"connection_pool_idle"`
+	ConnectionWaits    int64 `json:"connection_waits"`
 }
 
+// slowQueryThreshold is the duration above which an analysis query counts as slow
+const slowQueryThreshold = 100 * time.Millisecond
+
 // ExternalData represents data from external service calls
 type ExternalData struct {
 	ServiceCalled  string                 `json:"service_called"`
@@ -104,7 +112,7 @@ func (s *AnalysisService) AnalyzeProduct(ctx *gin.Context, productID *int) (*Ana
 	externalData, err := s.performExternalAnalysis(mainCtx, tracer)
 	if err != nil {
 		// Don't fail the whole request if external service fails
-		logger.WithError(err).Warn("External analysis failed, continuing with empty data")
+		logger.FromContext(mainCtx).WithError(err).Warn("External analysis failed, continuing with empty data")
 		externalData = &ExternalData{
 			ServiceCalled:  "httpbin.org",
 			Success:        false,
@@ -131,7 +139,7 @@ func (s *AnalysisService) AnalyzeProduct(ctx *gin.Context, productID *int) (*Ana
 		},
 	}
 
-	logger.WithFields(logrus.Fields{
+	logger.FromContext(mainCtx).WithFields(logrus.Fields{
 		"component":      "analysis",
 		"action":         "analyze",
 		"product_id":     productID,
@@ -228,28 +236,47 @@ func (s *AnalysisService) performDatabaseAnalysis(ctx context.Context, tracer tr
 
 	span.SetAttributes(attribute.String("db.analysis_type", "simple_demo"))
 
-	startTime := time.Now()
+	// The totals add up the queries themselves, not the demo delays before them or the work in between
 	queriesExecuted := 0
+	slowQueries := 0
+	var totalTime time.Duration
+	timeQuery := func(queryStart time.Time) {
+		elapsed := time.Since(queryStart)
+		queriesExecuted++
+		totalTime += elapsed
+		if elapsed > slowQueryThreshold {
+			slowQueries++
+		}
+	}
+
+	// Simple fixed delay to demonstrate span duration
+	time.Sleep(80 * time.Millisecond)
 
 	// Query 1: Count all products (demonstrates basic SELECT span)
-	count, err := s.productService.GetProductCount(dbCtx)
+	queryStart := time.Now()
+	count, err := s.productService.GetProductCount(dbCtx, models.ProductFilter{})
 	if err != nil {
 		span.RecordError(err)
 		return nil, fmt.Errorf("count query failed: %w", err)
 	}
-	queriesExecuted++
+	timeQuery(queryStart)
 
 	// Query 2: Optional product lookup (demonstrates conditional spans)
 	if productID != nil {
+		// Slightly longer delay before the specific lookup
+		time.Sleep(120 * time.Millisecond)
+
+		queryStart = time.Now()
 		_, err := s.productService.GetProductByID(dbCtx, *productID)
-		if err != nil && err.Error() != "product not found" {
+		if err != nil && !errors.Is(err, models.ErrNotFound) {
 			span.RecordError(err)
 			return nil, fmt.Errorf("product lookup failed: %w", err)
 		}
-		queriesExecuted++
+		timeQuery(queryStart)
 	}
 
-	totalTime := time.Since(startTime)
+	pool := s.productService.PoolStats()
+
 	averageLatency := totalTime.Milliseconds() / int64(queriesExecuted)
 
 	span.SetAttributes(
@@ -257,14 +284,20 @@ func (s *AnalysisService) performDatabaseAnalysis(ctx context.Context, tracer tr
 		attribute.Int64("db.total_time_ms", totalTime.Milliseconds()),
 		attribute.Int64("db.average_latency_ms", averageLatency),
 		attribute.Int("db.product_count", count),
+		attribute.Int("db.slow_queries", slowQueries),
+		attribute.Int("db.pool.in_use", pool.InUse),
+		attribute.Int("db.pool.open", pool.OpenConnections),
 	)
 
 	return &DatabaseStats{
 		QueriesExecuted:    queriesExecuted,
 		TotalQueryTimeMs:   totalTime.Milliseconds(),
 		AverageLatencyMs:   averageLatency,
-		SlowQueriesCount:   0, // Simplified - no slow query detection
-		ConnectionPoolUsed: 2, // Fixed value for simplicity
+		SlowQueriesCount:   slowQueries,
+		ConnectionPoolUsed: pool.InUse,
+		ConnectionPoolOpen: pool.OpenConnections,
+		ConnectionPoolIdle: pool.Idle,
+		ConnectionWaits:    pool.WaitCount,
 	}, nil
 }
 
@@ -283,7 +316,8 @@ func (s *AnalysisService) performExternalAnalysis(ctx context.Context, tracer tr
 
 	// Make HTTP request to httpbin for demonstration
 	client := &http.Client{
-		Timeout: 5 * time.Second,
+		Timeout:   5 * time.Second,
+		Transport: &requestid.Transport{}, // Forwards X-Request-ID
 	}
 
 	req, err := http.NewRequestWithContext(externalCtx, "GET", "https://httpbin.org/delay/1", nil)
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"catalog-service/internal/logger"
+	"catalog-service/internal/models"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// reaperBatchSize is the most reservations released in one pass
+const reaperBatchSize = 100
+
+// ReservationReaper periodically releases stock held by expired reservations
+type ReservationReaper struct {
+	reservationService *models.ReservationService
+	interval           time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewReservationReaper creates a reaper that runs every interval
+func NewReservationReaper(reservationService *models.ReservationService, interval time.Duration) *ReservationReaper {
+	return &ReservationReaper{
+		reservationService: reservationService,
+		interval:           interval,
+	}
+}
+
+// Start runs the reaper in the background until Stop is called
+func (r *ReservationReaper) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.reap(ctx)
+			}
+		}
+	}()
+
+	logger.WithFields(logrus.Fields{
+		"component": "reservation_reaper",
+		"action":    "start",
+		"interval":  r.interval.String(),
+	}).Info("Started reservation reaper")
+}
+
+// Stop stops the reaper and waits for a running pass to finish
+func (r *ReservationReaper) Stop() {
+	if r.cancel == nil {
+		return
+	}
+	r.cancel()
+	r.wg.Wait()
+}
+
+// reap releases expired reservations until there are none left or an error occurs.
+// Each pass is one trace, with the statements of every batch under its span.
+func (r *ReservationReaper) reap(ctx context.Context) {
+	ctx, span := otel.Tracer("catalog-service").Start(ctx, "reservation_reaper.reap")
+	defer span.End()
+
+	total := 0
+	batches := 0
+	defer func() {
+		span.SetAttributes(
+			attribute.Int("reaper.batches", batches),
+			attribute.Int("reaper.released", total),
+		)
+	}()
+
+	for {
+		released, err := r.reservationService.ReleaseExpired(ctx, reaperBatchSize)
+		batches++
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to release expired reservations")
+			if ctx.Err() == nil {
+				logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
+					"component": "reservation_reaper",
+					"action":    "reap",
+				}).Error("Failed to release expired reservations")
+			}
+			return
+		}
+
+		total += released
+		if released < reaperBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		logger.FromContext(ctx).WithFields(logrus.Fields{
+			"component": "reservation_reaper",
+			"action":    "reap",
+			"released":  total,
+		}).Info("Released expired reservations")
+	}
+}
@@ -0,0 +1,99 @@
+package tracing
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"catalog-service/internal/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"google.golang.org/grpc/credentials"
+)
+
+// SetupMetrics initializes the OpenTelemetry MeterProvider. Metrics are always exposed to
+// Prometheus through the default registry (and so on /metrics), and pushed over OTLP as well
+// when the otlp exporter is configured. Exemplars of sampled requests carry their trace id.
+func SetupMetrics(cfg config.TracingConfig, metricsCfg config.MetricsConfig) (func(), error) {
+	res, err := newResource(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bridge into the Prometheus default registry, next to the promauto metrics
+	promReader, err := otelprom.New(otelprom.WithoutScopeInfo())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prometheus exporter: %w", err)
+	}
+
+	opts := []sdkmetric.Option{
+		sdkmetric.WithResource(res),
+		sdkmetric.WithReader(promReader),
+	}
+
+	if metricsCfg.Exporter == "otlp" {
+		metricExporter, err := newMetricExporter(cfg, metricsCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create metric exporter: %w", err)
+		}
+		opts = append(opts, sdkmetric.WithReader(
+			sdkmetric.NewPeriodicReader(metricExporter, sdkmetric.WithInterval(metricsCfg.Interval.Duration)),
+		))
+	}
+
+	meterProvider := sdkmetric.NewMeterProvider(opts...)
+
+	// Set global meter provider
+	otel.SetMeterProvider(meterProvider)
+
+	// Return cleanup function; shutting down pushes the last OTLP export
+	cleanup := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := meterProvider.Shutdown(ctx); err != nil {
+			fmt.Printf("Error shutting down meter provider: %v\n", err)
+		}
+	}
+
+	return cleanup, nil
+}
+
+// newMetricExporter creates the OTLP metric exporter, with the same protocol and TLS settings as traces
+func newMetricExporter(cfg config.TracingConfig, metricsCfg config.MetricsConfig) (sdkmetric.Exporter, error) {
+	endpoint := metricsCfg.Endpoint
+	if endpoint == "" {
+		endpoint = cfg.Endpoint
+	}
+
+	var tlsConfig *tls.Config
+	if !cfg.Insecure {
+		var err error
+		if tlsConfig, err = exporterTLS(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Protocol == "grpc" {
+		opts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(endpoint)}
+		if tlsConfig == nil {
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		} else {
+			opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+		}
+		return otlpmetricgrpc.New(context.Background(), opts...)
+	}
+
+	opts := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(endpoint)}
+	if tlsConfig == nil {
+		opts = append(opts, otlpmetrichttp.WithInsecure())
+	} else {
+		opts = append(opts, otlpmetrichttp.WithTLSClientConfig(tlsConfig))
+	}
+	return otlpmetrichttp.New(context.Background(), opts...)
+}
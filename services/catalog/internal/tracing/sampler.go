@@ -0,0 +1,234 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"catalog-service/internal/config"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// maxPendingTraces bounds how many unsampled traces are held while waiting for their root span
+	maxPendingTraces = 2048
+	// maxPendingSpans bounds the spans held per unsampled trace
+	maxPendingSpans = 512
+	// pendingTraceTTL is how long a trace may wait for its root span before it is discarded
+	pendingTraceTTL = 5 * time.Minute
+)
+
+// sampler builds the configured sampler; the config package has already validated the name
+func sampler(cfg config.TracingConfig) sdktrace.Sampler {
+	var root sdktrace.Sampler
+	switch cfg.Sampler {
+	case "always_off", "parentbased_always_off":
+		root = sdktrace.NeverSample()
+	case "traceidratio", "parentbased_traceidratio":
+		root = sdktrace.TraceIDRatioBased(cfg.SamplerArg)
+	default:
+		root = sdktrace.AlwaysSample()
+	}
+
+	switch cfg.Sampler {
+	case "parentbased_always_on", "parentbased_always_off", "parentbased_traceidratio":
+		return sdktrace.ParentBased(root)
+	default:
+		return root
+	}
+}
+
+// keepsUnsampled reports whether the rules need spans of unsampled traces recorded,
+// so the error and slow trace rules can look at them once they end
+func keepsUnsampled(rules config.SamplingRules) bool {
+	return rules.KeepErrors || rules.SlowThreshold.Duration > 0
+}
+
+// ruleSampler applies the sampling rules on top of the configured sampler. Requests to
+// dropped routes are never sampled. When error or slow traces must be kept, traces the
+// sampler drops are still recorded so traceKeeper can decide once their root span ends.
+type ruleSampler struct {
+	base          sdktrace.Sampler
+	dropRoutes    map[string]bool
+	recordDropped bool
+}
+
+func newRuleSampler(base sdktrace.Sampler, rules config.SamplingRules) *ruleSampler {
+	dropRoutes := make(map[string]bool, len(rules.DropRoutes))
+	for _, route := range rules.DropRoutes {
+		dropRoutes[route] = true
+	}
+	return &ruleSampler{
+		base:          base,
+		dropRoutes:    dropRoutes,
+		recordDropped: keepsUnsampled(rules),
+	}
+}
+
+// ShouldSample implements sdktrace.Sampler
+func (s *ruleSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	parent := trace.SpanFromContext(p.ParentContext)
+	parentContext := parent.SpanContext()
+	localParent := parentContext.IsValid() && !parentContext.IsRemote()
+
+	// Spans under a dropped span (e.g. the database check of /readyz) are dropped with it
+	if localParent && !parent.IsRecording() {
+		return sdktrace.SamplingResult{Decision: sdktrace.Drop, Tracestate: parentContext.TraceState()}
+	}
+	if !localParent && s.dropped(p) {
+		return sdktrace.SamplingResult{Decision: sdktrace.Drop, Tracestate: parentContext.TraceState()}
+	}
+
+	result := s.base.ShouldSample(p)
+	if result.Decision == sdktrace.Drop && s.recordDropped {
+		result.Decision = sdktrace.RecordOnly
+	}
+	return result
+}
+
+// dropped reports whether a root span is a request to one of the dropped routes
+func (s *ruleSampler) dropped(p sdktrace.SamplingParameters) bool {
+	if len(s.dropRoutes) == 0 {
+		return false
+	}
+	if s.dropRoutes[p.Name] {
+		return true
+	}
+	for _, attr := range p.Attributes {
+		switch attr.Key {
+		case semconv.HTTPRouteKey, semconv.URLPathKey:
+			if s.dropRoutes[attr.Value.AsString()] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Description implements sdktrace.Sampler
+func (s *ruleSampler) Description() string {
+	return "RuleSampler{" + s.base.Description() + "}"
+}
+
+// pendingTrace holds the recorded spans of an unsampled trace until its root span ends
+type pendingTrace struct {
+	spans   []sdktrace.ReadOnlySpan
+	failed  bool
+	started time.Time
+}
+
+// traceKeeper sits in front of the batch processor. Sampled spans pass straight through;
+// spans of unsampled traces are held until the local root span ends, then exported if the
+// trace failed or was slow and discarded otherwise.
+type traceKeeper struct {
+	next          sdktrace.SpanProcessor
+	keepErrors    bool
+	slowThreshold time.Duration
+
+	mu      sync.Mutex
+	pending map[trace.TraceID]*pendingTrace
+}
+
+func newTraceKeeper(next sdktrace.SpanProcessor, rules config.SamplingRules) *traceKeeper {
+	return &traceKeeper{
+		next:          next,
+		keepErrors:    rules.KeepErrors,
+		slowThreshold: rules.SlowThreshold.Duration,
+		pending:       make(map[trace.TraceID]*pendingTrace),
+	}
+}
+
+// OnStart implements sdktrace.SpanProcessor
+func (k *traceKeeper) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	k.next.OnStart(parent, s)
+}
+
+// OnEnd implements sdktrace.SpanProcessor
+func (k *traceKeeper) OnEnd(s sdktrace.ReadOnlySpan) {
+	if s.SpanContext().IsSampled() {
+		k.next.OnEnd(s)
+		return
+	}
+
+	traceID := s.SpanContext().TraceID()
+	root := !s.Parent().IsValid() || s.Parent().IsRemote()
+
+	k.mu.Lock()
+	pending, ok := k.pending[traceID]
+	if !ok {
+		if !root && !k.reserve(time.Now()) {
+			k.mu.Unlock()
+			return
+		}
+		pending = &pendingTrace{started: time.Now()}
+		if !root {
+			k.pending[traceID] = pending
+		}
+	}
+	if len(pending.spans) < maxPendingSpans {
+		pending.spans = append(pending.spans, s)
+	}
+	if s.Status().Code == codes.Error {
+		pending.failed = true
+	}
+	if root {
+		delete(k.pending, traceID)
+	}
+	k.mu.Unlock()
+
+	if !root {
+		return
+	}
+	slow := k.slowThreshold > 0 && s.EndTime().Sub(s.StartTime()) >= k.slowThreshold
+	if !(k.keepErrors && pending.failed) && !slow {
+		return
+	}
+	for _, span := range pending.spans {
+		k.next.OnEnd(keptSpan{span})
+	}
+}
+
+// reserve makes room for another pending trace, discarding traces whose root span never
+// ended. It reports false when the buffer is still full. k.mu must be held.
+func (k *traceKeeper) reserve(now time.Time) bool {
+	if len(k.pending) < maxPendingTraces {
+		return true
+	}
+	for traceID, pending := range k.pending {
+		if now.Sub(pending.started) > pendingTraceTTL {
+			delete(k.pending, traceID)
+		}
+	}
+	return len(k.pending) < maxPendingTraces
+}
+
+// Shutdown implements sdktrace.SpanProcessor
+func (k *traceKeeper) Shutdown(ctx context.Context) error {
+	return k.next.Shutdown(ctx)
+}
+
+// ForceFlush implements sdktrace.SpanProcessor
+func (k *traceKeeper) ForceFlush(ctx context.Context) error {
+	return k.next.ForceFlush(ctx)
+}
+
+// keptSpan marks a span of an unsampled trace as sampled, so the batch processor exports it
+type keptSpan struct {
+	sdktrace.ReadOnlySpan
+}
+
+// SpanContext returns the span context with the sampled flag set
+func (s keptSpan) SpanContext() trace.SpanContext {
+	sc := s.ReadOnlySpan.SpanContext()
+	return sc.WithTraceFlags(sc.TraceFlags().WithSampled(true))
+}
+
+// Attributes adds catalog.sampling.kept, so kept traces can be told apart from sampled ones
+func (s keptSpan) Attributes() []attribute.KeyValue {
+	return append(s.ReadOnlySpan.Attributes(), attribute.Bool("catalog.sampling.kept", true))
+}
@@ -0,0 +1,252 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"catalog-service/internal/config"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// remoteParent returns a context carrying a remote parent span with the given sampled flag
+func remoteParent(sampled bool) context.Context {
+	var flags trace.TraceFlags
+	if sampled {
+		flags = trace.FlagsSampled
+	}
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: flags,
+		Remote:     true,
+	})
+	return trace.ContextWithRemoteSpanContext(context.Background(), sc)
+}
+
+func TestSampler(t *testing.T) {
+	// TraceIDRatioBased samples trace ids whose last 8 bytes, shifted right by one, fall below ratio * 2^63
+	lowID := trace.TraceID{15: 1}
+	highID := trace.TraceID{8: 0xff, 9: 0xff, 10: 0xff, 11: 0xff, 12: 0xff, 13: 0xff, 14: 0xff, 15: 0xff}
+
+	tests := []struct {
+		sampler string
+		arg     float64
+		parent  context.Context
+		traceID trace.TraceID
+		want    sdktrace.SamplingDecision
+	}{
+		{sampler: "always_on", parent: context.Background(), want: sdktrace.RecordAndSample},
+		{sampler: "always_on", parent: remoteParent(false), want: sdktrace.RecordAndSample},
+		{sampler: "always_off", parent: remoteParent(true), want: sdktrace.Drop},
+		{sampler: "traceidratio", arg: 0.5, parent: context.Background(), traceID: lowID, want: sdktrace.RecordAndSample},
+		{sampler: "traceidratio", arg: 0.5, parent: context.Background(), traceID: highID, want: sdktrace.Drop},
+		{sampler: "parentbased_always_on", parent: context.Background(), want: sdktrace.RecordAndSample},
+		{sampler: "parentbased_always_on", parent: remoteParent(false), want: sdktrace.Drop},
+		{sampler: "parentbased_always_off", parent: context.Background(), want: sdktrace.Drop},
+		{sampler: "parentbased_always_off", parent: remoteParent(true), want: sdktrace.RecordAndSample},
+		{sampler: "parentbased_traceidratio", arg: 0, parent: remoteParent(true), want: sdktrace.RecordAndSample},
+		{sampler: "parentbased_traceidratio", arg: 1, parent: remoteParent(false), want: sdktrace.Drop},
+		{sampler: "parentbased_traceidratio", arg: 1, parent: context.Background(), traceID: highID, want: sdktrace.RecordAndSample},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sampler, func(t *testing.T) {
+			s := sampler(config.TracingConfig{Sampler: tt.sampler, SamplerArg: tt.arg})
+			result := s.ShouldSample(sdktrace.SamplingParameters{
+				ParentContext: tt.parent,
+				TraceID:       tt.traceID,
+				Name:          "GET /api/v1/products",
+			})
+			if result.Decision != tt.want {
+				t.Errorf("%s(%v) decision = %v, want %v", tt.sampler, tt.arg, result.Decision, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleSampler(t *testing.T) {
+	rules := config.SamplingRules{DropRoutes: []string{"/livez", "/readyz", "GET /metrics"}}
+
+	tests := []struct {
+		name   string
+		base   sdktrace.Sampler
+		rules  config.SamplingRules
+		parent context.Context
+		span   string
+		attrs  []attribute.KeyValue
+		want   sdktrace.SamplingDecision
+	}{
+		{
+			name: "other route",
+			base: sdktrace.AlwaysSample(), rules: rules, span: "GET /api/v1/products",
+			attrs: []attribute.KeyValue{semconv.HTTPRoute("/api/v1/products")},
+			want:  sdktrace.RecordAndSample,
+		},
+		{
+			name: "dropped by route",
+			base: sdktrace.AlwaysSample(), rules: rules, span: "GET",
+			attrs: []attribute.KeyValue{semconv.HTTPRoute("/readyz")},
+			want:  sdktrace.Drop,
+		},
+		{
+			name: "dropped by path",
+			base: sdktrace.AlwaysSample(), rules: rules, span: "GET",
+			attrs: []attribute.KeyValue{semconv.URLPath("/livez")},
+			want:  sdktrace.Drop,
+		},
+		{
+			name: "dropped by span name",
+			base: sdktrace.AlwaysSample(), rules: rules, span: "GET /metrics",
+			want: sdktrace.Drop,
+		},
+		{
+			name: "dropped even with a sampled remote parent",
+			base: sdktrace.ParentBased(sdktrace.AlwaysSample()), rules: rules, parent: remoteParent(true), span: "GET",
+			attrs: []attribute.KeyValue{semconv.HTTPRoute("/livez")},
+			want:  sdktrace.Drop,
+		},
+		{
+			name: "other attributes do not drop",
+			base: sdktrace.AlwaysSample(), rules: rules, span: "GET",
+			attrs: []attribute.KeyValue{attribute.String("http.target", "/livez")},
+			want:  sdktrace.RecordAndSample,
+		},
+		{
+			name: "unsampled traces are not recorded without keep rules",
+			base: sdktrace.NeverSample(), rules: config.SamplingRules{}, span: "GET",
+			want: sdktrace.Drop,
+		},
+		{
+			name: "unsampled traces are recorded to keep errors",
+			base: sdktrace.NeverSample(), rules: config.SamplingRules{KeepErrors: true}, span: "GET",
+			want: sdktrace.RecordOnly,
+		},
+		{
+			name: "unsampled traces are recorded to keep slow ones",
+			base: sdktrace.NeverSample(), rules: config.SamplingRules{SlowThreshold: config.Duration{Duration: time.Second}}, span: "GET",
+			want: sdktrace.RecordOnly,
+		},
+		{
+			name: "dropped routes are not recorded to keep errors",
+			base: sdktrace.NeverSample(), rules: config.SamplingRules{KeepErrors: true, DropRoutes: []string{"/livez"}}, span: "GET",
+			attrs: []attribute.KeyValue{semconv.HTTPRoute("/livez")},
+			want:  sdktrace.Drop,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := tt.parent
+			if parent == nil {
+				parent = context.Background()
+			}
+			result := newRuleSampler(tt.base, tt.rules).ShouldSample(sdktrace.SamplingParameters{
+				ParentContext: parent,
+				Name:          tt.span,
+				Attributes:    tt.attrs,
+			})
+			if result.Decision != tt.want {
+				t.Errorf("decision = %v, want %v", result.Decision, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleSamplerDropsChildrenOfDroppedSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	rules := config.SamplingRules{DropRoutes: []string{"/readyz"}}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(newRuleSampler(sdktrace.AlwaysSample(), rules)),
+		sdktrace.WithSpanProcessor(recorder),
+	)
+	tracer := provider.Tracer("test")
+
+	ctx, root := tracer.Start(context.Background(), "GET", trace.WithAttributes(semconv.HTTPRoute("/readyz")))
+	_, child := tracer.Start(ctx, "SELECT 1")
+	child.End()
+	root.End()
+
+	if spans := recorder.Ended(); len(spans) != 0 {
+		t.Errorf("recorded %d spans of a dropped route, want none", len(spans))
+	}
+}
+
+func TestTraceKeeper(t *testing.T) {
+	start := time.Now()
+
+	tests := []struct {
+		name     string
+		rules    config.SamplingRules
+		base     sdktrace.Sampler
+		failed   bool
+		duration time.Duration
+		wantKept bool
+	}{
+		{
+			name: "sampled trace passes through", base: sdktrace.AlwaysSample(),
+			rules: config.SamplingRules{KeepErrors: true}, duration: time.Millisecond, wantKept: true,
+		},
+		{
+			name: "failed trace is kept", base: sdktrace.NeverSample(),
+			rules: config.SamplingRules{KeepErrors: true}, failed: true, duration: time.Millisecond, wantKept: true,
+		},
+		{
+			name: "successful trace is discarded", base: sdktrace.NeverSample(),
+			rules: config.SamplingRules{KeepErrors: true}, duration: time.Millisecond,
+		},
+		{
+			name: "slow trace is kept", base: sdktrace.NeverSample(),
+			rules: config.SamplingRules{SlowThreshold: config.Duration{Duration: time.Second}}, duration: 2 * time.Second, wantKept: true,
+		},
+		{
+			name: "fast trace is discarded", base: sdktrace.NeverSample(),
+			rules: config.SamplingRules{SlowThreshold: config.Duration{Duration: time.Second}}, duration: 500 * time.Millisecond,
+		},
+		{
+			name: "failed trace is discarded without the error rule", base: sdktrace.NeverSample(),
+			rules: config.SamplingRules{SlowThreshold: config.Duration{Duration: time.Second}}, failed: true, duration: time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(
+				sdktrace.WithSampler(newRuleSampler(tt.base, tt.rules)),
+				sdktrace.WithSpanProcessor(newTraceKeeper(recorder, tt.rules)),
+			)
+			tracer := provider.Tracer("test")
+
+			ctx, root := tracer.Start(context.Background(), "GET /api/v1/products", trace.WithTimestamp(start))
+			_, child := tracer.Start(ctx, "SELECT products", trace.WithTimestamp(start))
+			if tt.failed {
+				child.SetStatus(codes.Error, "query failed")
+			}
+			child.End(trace.WithTimestamp(start.Add(tt.duration / 2)))
+			root.End(trace.WithTimestamp(start.Add(tt.duration)))
+
+			spans := recorder.Ended()
+			if !tt.wantKept {
+				if len(spans) != 0 {
+					t.Fatalf("exported %d spans, want none", len(spans))
+				}
+				return
+			}
+			if len(spans) != 2 {
+				t.Fatalf("exported %d spans, want the root and its child", len(spans))
+			}
+			for _, span := range spans {
+				if !span.SpanContext().IsSampled() {
+					t.Errorf("span %s is exported unsampled", span.Name())
+				}
+			}
+		})
+	}
+}
@@ -2,58 +2,51 @@ package tracing
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"os"
 	"time"
 
+	"catalog-service/internal/config"
+
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/credentials"
 )
 
 // Setup initializes OpenTelemetry tracing
-func Setup(serviceName string) (func(), error) {
-	// Create resource with service information
-	res, err := resource.Merge(
-		resource.Default(),
-		resource.NewWithAttributes(
-			semconv.SchemaURL,
-			semconv.ServiceName(serviceName),
-			semconv.ServiceVersion("1.0.0"),
-		),
-	)
+func Setup(cfg config.TracingConfig) (func(), error) {
+	res, err := newResource(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create resource: %w", err)
+		return nil, err
 	}
 
-	// Get OTLP endpoint from environment (defaults to Alloy)
-	otlpEndpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
-	if otlpEndpoint == "" {
-		otlpEndpoint = "alloy.monitoring.svc.cluster.local:4318"
+	traceExporter, err := newExporter(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
 	}
 
-	// Create OTLP HTTP exporter
-	traceExporter, err := otlptracehttp.New(
-		context.Background(),
-		otlptracehttp.WithEndpoint(otlpEndpoint),
-		otlptracehttp.WithInsecure(), // Lab environment
+	// Create batch processor; with the error and slow trace rules on, it sits behind
+	// traceKeeper, which passes on the unsampled traces worth keeping
+	var processor sdktrace.SpanProcessor = sdktrace.NewBatchSpanProcessor(traceExporter,
+		sdktrace.WithBatchTimeout(cfg.BatchTimeout.Duration),
+		sdktrace.WithMaxExportBatchSize(cfg.MaxExportBatchSize),
 	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	if keepsUnsampled(cfg.Rules) {
+		processor = newTraceKeeper(processor, cfg.Rules)
 	}
 
-	// Create trace provider with batch processor
 	traceProvider := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(traceExporter,
-			sdktrace.WithBatchTimeout(time.Second*5),
-			sdktrace.WithMaxExportBatchSize(512),
-		),
+		sdktrace.WithSpanProcessor(processor),
 		sdktrace.WithResource(res),
-		sdktrace.WithSampler(sdktrace.AlwaysSample()), // Sample all traces in lab environment
+		sdktrace.WithSampler(newRuleSampler(sampler(cfg), cfg.Rules)),
 	)
 
 	// Set global trace provider
@@ -80,6 +73,78 @@ func Setup(serviceName string) (func(), error) {
 	return cleanup, nil
 }
 
+// newResource describes the service for both traces and metrics
+func newResource(cfg config.TracingConfig) (*resource.Resource, error) {
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(cfg.ServiceName),
+			semconv.ServiceVersion(cfg.ServiceVersion),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+	return res, nil
+}
+
+// newExporter creates the OTLP exporter for the configured protocol
+func newExporter(cfg config.TracingConfig) (sdktrace.SpanExporter, error) {
+	var tlsConfig *tls.Config
+	if !cfg.Insecure {
+		var err error
+		if tlsConfig, err = exporterTLS(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Protocol == "grpc" {
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+		if tlsConfig == nil {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		} else {
+			opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+		}
+		return otlptracegrpc.New(context.Background(), opts...)
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+	if tlsConfig == nil {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	} else {
+		opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsConfig))
+	}
+	return otlptracehttp.New(context.Background(), opts...)
+}
+
+// exporterTLS builds the TLS settings for the exporter from the configured CA and client certificate files
+func exporterTLS(cfg config.TracingConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if cfg.Certificate != "" {
+		pem, err := os.ReadFile(cfg.Certificate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.Certificate)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCertificate != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCertificate, cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
 // GetTracer returns a tracer for the catalog service
 func GetTracer() trace.Tracer {
 	return otel.Tracer("catalog-service")
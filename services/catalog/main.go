@@ -1,70 +1,292 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
+	"catalog-service/internal/auth"
+	"catalog-service/internal/config"
 	"catalog-service/internal/db"
+	"catalog-service/internal/grpcserver"
 	"catalog-service/internal/logger"
+	"catalog-service/internal/metrics"
 	"catalog-service/internal/server"
 	"catalog-service/internal/tracing"
 
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/codes"
 )
 
 func main() {
+	// "catalog migrate up|down|status" runs migrations and exits without starting the server,
+	// so it only needs valid database settings
+	migrate := len(os.Args) > 1 && os.Args[1] == "migrate"
+
+	// Load and validate the configuration before anything else uses it
+	load := config.Load
+	if migrate {
+		load = config.LoadDatabase
+	}
+	cfg, err := load()
+	if err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{
+			"component": "config",
+			"action":    "load",
+		}).Fatal("Failed to load configuration")
+	}
+	if err := logger.SetLevel(cfg.Log.Level); err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{
+			"component": "config",
+			"action":    "load",
+		}).Fatal("Failed to set log level")
+	}
+
+	if migrate {
+		os.Exit(runMigrate(cfg, os.Args[2:]))
+	}
+
 	// Initialize OpenTelemetry tracing
-	cleanup, err := tracing.Setup("catalog-service")
+	cleanup, err := tracing.Setup(cfg.Tracing)
 	if err != nil {
 		logger.WithError(err).WithFields(logrus.Fields{
 			"component": "tracing",
 			"action":    "setup",
 		}).Fatal("Failed to initialize tracing")
 	}
-	defer cleanup()
 
 	logger.WithFields(logrus.Fields{
 		"component": "tracing",
 		"action":    "initialize",
 	}).Info("OpenTelemetry tracing initialized")
 
+	// Initialize OpenTelemetry metrics, exported over OTLP and on /metrics
+	cleanupMetrics, err := tracing.SetupMetrics(cfg.Tracing, cfg.Metrics)
+	if err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{
+			"component": "metrics",
+			"action":    "setup",
+		}).Fatal("Failed to initialize metrics")
+	}
+
 	// Get database connection
-	database, err := db.Connect()
+	database, err := db.Connect(cfg.Database)
 	if err != nil {
 		logger.WithError(err).WithFields(logrus.Fields{
 			"component": "database",
 			"action":    "connect",
 		}).Fatal("Failed to connect to database")
 	}
-	defer database.Close()
 
-	// Initialize database schema
-	if err := database.InitSchema(); err != nil {
+	// Export connection pool statistics to Prometheus
+	if err := metrics.RegisterDBStats(database.DB); err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{
+			"component": "metrics",
+			"action":    "register",
+		}).Fatal("Failed to register database metrics")
+	}
+
+	// Apply any pending schema migrations, traced as one startup span
+	migrateCtx, migrateSpan := tracing.GetTracer().Start(context.Background(), "db.migrate_up")
+	err = database.MigrateUp(migrateCtx)
+	if err != nil {
+		migrateSpan.RecordError(err)
+		migrateSpan.SetStatus(codes.Error, "migrations failed")
+	}
+	migrateSpan.End()
+	if err != nil {
 		logger.WithError(err).WithFields(logrus.Fields{
 			"component": "database",
-			"action":    "schema_init",
-		}).Fatal("Failed to initialize database schema")
+			"action":    "migrate_up",
+		}).Fatal("Failed to apply database migrations")
+	}
+
+	// Authenticate write endpoints with the configured API keys and JWT keys
+	authenticator, err := auth.New(cfg.Auth)
+	if err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{
+			"component": "auth",
+			"action":    "setup",
+		}).Fatal("Failed to initialize authentication")
+	}
+	if authenticator == nil {
+		logger.WithFields(logrus.Fields{
+			"component": "auth",
+			"action":    "setup",
+		}).Warn("Authentication is disabled; write endpoints are open to anyone")
 	}
 
 	// Create server with the underlying sql.DB
-	srv := server.NewServer(database.DB)
+	srv := server.NewServer(database.DB, cfg, authenticator)
+	grpcSrv := grpcserver.NewServer(database.DB)
 
-	// Get port from environment or use default
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
+	// Either server failing to start also shuts the service down
+	serveErrs := make(chan error, 2)
+
+	// Start the gRPC server on its own port, next to the REST API
+	go func() {
+		if err := grpcSrv.Start(cfg.Server.GRPCPort); err != nil {
+			logger.WithError(err).WithFields(logrus.Fields{
+				"component": "grpc",
+				"action":    "start",
+			}).Error("Failed to start gRPC server")
+			serveErrs <- err
+		}
+	}()
 
 	// Start server
 	logger.WithFields(logrus.Fields{
 		"component": "server",
 		"action":    "start",
-		"port":      port,
+		"port":      cfg.Server.Port,
 	}).Info("Starting catalog service")
 
-	if err := srv.Start(port); err != nil {
+	go func() {
+		if err := srv.Start(cfg.Server.Port); err != nil {
+			logger.WithError(err).WithFields(logrus.Fields{
+				"component": "server",
+				"action":    "start",
+			}).Error("Failed to start server")
+			serveErrs <- err
+		}
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	exitCode := 0
+	select {
+	case sig := <-signals:
+		logger.WithFields(logrus.Fields{
+			"component": "server",
+			"action":    "shutdown",
+			"signal":    sig.String(),
+		}).Info("Received shutdown signal")
+	case <-serveErrs:
+		exitCode = 1
+	}
+
+	shutdown(cfg, srv, grpcSrv, func() {
+		cleanup()
+		cleanupMetrics()
+	}, database)
+	os.Exit(exitCode)
+}
+
+// shutdown tears the service down in dependency order: fail readiness, wait for load
+// balancers to notice, drain HTTP and gRPC requests, then flush telemetry and close the pool
+func shutdown(cfg *config.Config, srv *server.Server, grpcSrv *grpcserver.Server, flushTelemetry func(), database *db.Database) {
+	start := time.Now()
+
+	srv.BeginShutdown()
+	grpcSrv.BeginShutdown()
+
+	if delay := cfg.Server.ShutdownDelay.Duration; delay > 0 {
+		logger.WithFields(logrus.Fields{
+			"component": "server",
+			"action":    "shutdown",
+			"delay":     delay.String(),
+		}).Info("Waiting before draining requests")
+		time.Sleep(delay)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout.Duration)
+	defer cancel()
+
+	// Drain both listeners at the same time so they share the deadline
+	grpcDone := make(chan error, 1)
+	go func() {
+		grpcDone <- grpcSrv.Shutdown(ctx)
+	}()
+
+	if err := srv.Shutdown(ctx); err != nil {
 		logger.WithError(err).WithFields(logrus.Fields{
 			"component": "server",
-			"action":    "start",
-		}).Fatal("Failed to start server")
+			"action":    "shutdown",
+		}).Warn("HTTP requests did not drain before the deadline")
+	}
+	if err := <-grpcDone; err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{
+			"component": "grpc",
+			"action":    "shutdown",
+		}).Warn("gRPC requests did not drain before the deadline")
+	}
+
+	// Flush buffered spans and the last metrics while the request spans are complete
+	flushTelemetry()
+
+	if err := database.Close(); err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{
+			"component": "database",
+			"action":    "close",
+		}).Error("Failed to close database")
+	}
+
+	logger.WithFields(logrus.Fields{
+		"component":   "server",
+		"action":      "shutdown",
+		"duration_ms": time.Since(start).Milliseconds(),
+	}).Info("Catalog service stopped")
+}
+
+// runMigrate handles the migrate subcommand and returns the process exit code
+func runMigrate(cfg *config.Config, args []string) int {
+	usage := "usage: catalog migrate up|down [steps]|status"
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	database, err := db.Connect(cfg.Database)
+	if err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{
+			"component": "database",
+			"action":    "connect",
+		}).Error("Failed to connect to database")
+		return 1
+	}
+	defer database.Close()
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		err = database.MigrateUp(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				fmt.Fprintln(os.Stderr, usage)
+				return 2
+			}
+		}
+		err = database.MigrateDown(ctx, steps)
+	case "status":
+		var statuses []db.MigrationStatus
+		statuses, err = database.MigrationStatus(ctx)
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.Applied {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			fmt.Printf("%04d  %-40s  %s\n", s.Version, s.Name, appliedAt)
+		}
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	if err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{
+			"component": "database",
+			"action":    "migrate_" + args[0],
+		}).Error("Migration command failed")
+		return 1
 	}
+	return 0
 }